@@ -0,0 +1,287 @@
+package teledau
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BroadcastStatus* are the states of one chat in a broadcast.
+const (
+	BroadcastStatusPending = iota
+	BroadcastStatusSent
+	// BroadcastStatusFailed chats are sent to again when the broadcast is resumed.
+	BroadcastStatusFailed
+	// BroadcastStatusUnavailable means the bot was kicked or blocked, or the chat doesn't exist.
+	BroadcastStatusUnavailable
+)
+
+// BroadcastResult is the outcome of a broadcast for one chat.
+type BroadcastResult struct {
+	ChatId     string  `json:"chat_id"`
+	Status     int     `json:"status"`
+	MessageIds []int64 `json:"message_ids,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	ErrorCode  int     `json:"error_code,omitempty"`
+	Attempts   int     `json:"attempts,omitempty"`
+	// MigratedTo is the supergroup a group was upgraded to; the post was sent there instead.
+	MigratedTo int64 `json:"migrated_to,omitempty"`
+}
+
+// BroadcastReport holds the results of a broadcast in the order of its channels.
+type BroadcastReport struct {
+	Id      string
+	Results []BroadcastResult
+}
+
+// Sent returns the number of chats the post was delivered to.
+func (r *BroadcastReport) Sent() int {
+	return r.count(BroadcastStatusSent)
+}
+
+// Failed returns the number of chats the post wasn't delivered to, unavailable ones included.
+func (r *BroadcastReport) Failed() int {
+	return len(r.Results) - r.Sent() - r.count(BroadcastStatusPending)
+}
+
+func (r *BroadcastReport) count(status int) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Broadcaster sends one post to many chats concurrently. Workers chats are served at once
+// and every message waits for Limiter first; flood-control errors are retried after the delay
+// the Bot API asks for.
+//
+// Results are saved in Storage as soon as a chat is done, so running the same broadcast id again
+// after a crash only sends to the chats that weren't reached yet. A chat whose message was sent right
+// before the crash, but not recorded, gets the post twice.
+type Broadcaster struct {
+	Client  Telegram
+	Storage Storage
+	Limiter RateLimiter
+	Workers int
+	// MaxAttempts limits the attempts per chat within one run.
+	MaxAttempts int
+	// RetryDelay is the wait before retrying a server error.
+	RetryDelay time.Duration
+	// Prefix is prepended to every storage key.
+	Prefix string
+}
+
+func NewBroadcaster(client Telegram, storage Storage) *Broadcaster {
+	return &Broadcaster{
+		Client:      client,
+		Storage:     storage,
+		Limiter:     NewIntervalRateLimiter(),
+		Workers:     8,
+		MaxAttempts: 3,
+		RetryDelay:  time.Second,
+		Prefix:      "broadcast:",
+	}
+}
+
+// Broadcast sends post to every channel and returns the result for each of them. Post.ChatId is ignored.
+// Chats already sent to, or found unavailable, in an earlier run with the same id are skipped.
+// Only storage errors and the end of ctx are returned; delivery errors are in the report.
+func (b *Broadcaster) Broadcast(ctx context.Context, id string, post Post, channels []Channel) (*BroadcastReport, error) {
+	report := &BroadcastReport{Id: id, Results: make([]BroadcastResult, len(channels))}
+
+	var pending []int
+	for i, channel := range channels {
+		result, err := b.Result(id, channelChatId(channel))
+		if err != nil {
+			return nil, err
+		}
+
+		report.Results[i] = *result
+		if result.Status == BroadcastStatusPending || result.Status == BroadcastStatusFailed {
+			pending = append(pending, i)
+		}
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, 1)
+
+	workers := b.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				result := b.send(ctx, post, report.Results[i])
+				report.Results[i] = result
+
+				if err := b.save(id, result); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for _, i := range pending {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return report, err
+	default:
+	}
+
+	return report, ctx.Err()
+}
+
+// Result returns the saved result of a broadcast for chatId; a chat that wasn't sent to yet is pending.
+func (b *Broadcaster) Result(id, chatId string) (*BroadcastResult, error) {
+	data, err := b.Storage.Get(b.key(id, chatId))
+	if errors.Is(err, ErrStorageNotFound) {
+		return &BroadcastResult{ChatId: chatId}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var result BroadcastResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// send delivers post to the chat of result, retrying temporary errors.
+func (b *Broadcaster) send(ctx context.Context, post Post, result BroadcastResult) BroadcastResult {
+	// A failed result keeps the messages sent before the error, so only the rest is sent.
+	request := PostRequest{ChatUsername: result.ChatId, Text: post.Caption, Img: post.Photo, MessageIds: result.MessageIds}
+	if post.IsMarkdown {
+		request.ParseMode = TgParseModMarkdownV2
+	}
+
+	if result.MigratedTo != 0 {
+		request.ChatUsername = strconv.FormatInt(result.MigratedTo, 10)
+	}
+
+	maxAttempts := b.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if b.Limiter != nil {
+			if err := b.Limiter.Wait(ctx, request.ChatUsername); err != nil {
+				return result
+			}
+		}
+
+		result.Attempts++
+
+		messageIds, err := sendPost(b.Client, request)
+		if len(messageIds) > 0 {
+			result.MessageIds, request.MessageIds = messageIds, messageIds
+		}
+
+		if err == nil {
+			result.Status = BroadcastStatusSent
+			result.Error, result.ErrorCode = "", 0
+
+			return result
+		}
+
+		log.Printf("Error broadcasting to %s: %v", request.ChatUsername, err)
+		result.Status, result.Error = BroadcastStatusFailed, err.Error()
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			return result
+		}
+
+		result.ErrorCode = apiErr.ErrorCode
+
+		if apiErr.MigrateTo != 0 {
+			// The whole post goes to the supergroup.
+			result.MigratedTo, result.MessageIds = apiErr.MigrateTo, nil
+			request.ChatUsername, request.MessageIds = strconv.FormatInt(apiErr.MigrateTo, 10), nil
+
+			continue
+		}
+
+		if apiErr.ChatUnavailable() {
+			result.Status = BroadcastStatusUnavailable
+
+			return result
+		}
+
+		if !apiErr.Temporary() {
+			return result
+		}
+
+		delay := b.RetryDelay
+		if apiErr.RetryAfter > 0 {
+			delay = time.Duration(apiErr.RetryAfter) * time.Second
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return result
+		}
+	}
+
+	return result
+}
+
+func (b *Broadcaster) save(id string, result BroadcastResult) error {
+	if result.Status == BroadcastStatusPending {
+		return nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	return b.Storage.Set(b.key(id, result.ChatId), data, 0)
+}
+
+func (b *Broadcaster) key(id, chatId string) string {
+	return b.Prefix + id + ":" + chatId
+}
+
+// channelChatId returns the chat id to send to: ChatID, else Username, else Id.
+func channelChatId(channel Channel) string {
+	if len(channel.ChatID) > 0 {
+		return channel.ChatID
+	}
+
+	if len(channel.Username) > 0 {
+		return channel.Username
+	}
+
+	return strconv.Itoa(channel.Id)
+}
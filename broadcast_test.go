@@ -0,0 +1,119 @@
+package teledau
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBroadcaster_Broadcast(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	var mu sync.Mutex
+	down := true
+	transport.handler = func(method string, body []byte) (int, string) {
+		var request MessageRequest
+		_ = json.Unmarshal(body, &request)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch request.ChatId {
+		case "@kicked":
+			return http.StatusForbidden, `{"ok":false,"error_code":403,"description":"Forbidden: bot was kicked from the channel chat"}`
+		case "-1":
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-1001}}`
+		case "@flaky":
+			if down {
+				return http.StatusBadGateway, `{"ok":false,"error_code":502,"description":"Bad Gateway"}`
+			}
+		}
+
+		return http.StatusOK, `{"ok":true,"result":{"message_id":7}}`
+	}
+
+	broadcaster := NewBroadcaster(client, NewMemoryStorage())
+	broadcaster.Limiter = nil
+	broadcaster.RetryDelay = time.Millisecond
+
+	channels := []Channel{{ChatID: "@news"}, {Username: "@kicked"}, {Id: -1}, {ChatID: "@flaky"}}
+	post := Post{Caption: "Round 5 results"}
+
+	report, err := broadcaster.Broadcast(context.Background(), "round-5", post, channels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{BroadcastStatusSent, BroadcastStatusUnavailable, BroadcastStatusSent, BroadcastStatusFailed}
+	for i, result := range report.Results {
+		if result.Status != want[i] {
+			t.Errorf("%s: status = %d, want %d (%s)", result.ChatId, result.Status, want[i], result.Error)
+		}
+	}
+
+	if migrated := report.Results[2]; migrated.MigratedTo != -1001 || len(migrated.MessageIds) != 1 {
+		t.Errorf("unexpected result for migrated group: %+v", migrated)
+	}
+
+	if flaky := report.Results[3]; flaky.Attempts != broadcaster.MaxAttempts {
+		t.Errorf("flaky chat attempts = %d, want %d", flaky.Attempts, broadcaster.MaxAttempts)
+	}
+
+	if report.Sent() != 2 || report.Failed() != 2 {
+		t.Errorf("Sent() = %d, Failed() = %d, want 2 and 2", report.Sent(), report.Failed())
+	}
+
+	mu.Lock()
+	down = false
+	mu.Unlock()
+
+	sent := len(transport.calls("sendMessage"))
+
+	report, err = broadcaster.Broadcast(context.Background(), "round-5", post, channels)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := len(transport.calls("sendMessage")) - sent; calls != 1 {
+		t.Errorf("resumed broadcast sent %d messages, want 1", calls)
+	}
+
+	if report.Sent() != 3 || report.Results[3].Status != BroadcastStatusSent {
+		t.Errorf("unexpected report after resume: %+v", report.Results)
+	}
+}
+
+func TestBroadcaster_ResumesPartlySentPost(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	calls := 0
+	transport.handler = func(method string, body []byte) (int, string) {
+		calls++
+		if calls == 2 {
+			return http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests","parameters":{"retry_after":0}}`
+		}
+
+		return http.StatusOK, `{"ok":true,"result":{"message_id":7}}`
+	}
+
+	broadcaster := NewBroadcaster(client, NewMemoryStorage())
+	broadcaster.Limiter = nil
+	broadcaster.RetryDelay = time.Millisecond
+
+	post := Post{Caption: strings.Repeat("a", MaxMessageLength) + " b"}
+	report, err := broadcaster.Broadcast(context.Background(), "long", post, []Channel{{ChatID: "@news"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result := report.Results[0]; result.Status != BroadcastStatusSent || len(result.MessageIds) != 2 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	if sent := transport.calls("sendMessage"); len(sent) != 3 || !strings.Contains(string(sent[2].Body), `"text":"b"`) {
+		t.Errorf("only the failed part should be sent again, got %d calls", len(sent))
+	}
+}
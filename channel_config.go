@@ -0,0 +1,553 @@
+package teledau
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChannelsConfig is the file format read by LoadChannelsConfig, in JSON:
+//
+//	{"channels": [{"chat_id": "@club", "title": "Club", "permissions": {"can_send_messages": true}}]}
+//
+// or in YAML:
+//
+//	channels:
+//	  - chat_id: "@club"
+//	    title: Club
+//	    permissions:
+//	      can_send_messages: true
+type ChannelsConfig struct {
+	Channels []ChannelConfig `json:"channels" yaml:"channels"`
+}
+
+// ChannelConfig is the desired state of a chat. Settings left empty are not managed
+// and keep whatever value the chat has.
+type ChannelConfig struct {
+	ChatId string `json:"chat_id" yaml:"chat_id"`
+	Title  string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Description is managed when set; an empty string removes the description.
+	Description *string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Photo is the path of the chat photo, relative to the config file.
+	Photo         string               `json:"photo,omitempty" yaml:"photo,omitempty"`
+	PinnedMessage *PinnedMessageConfig `json:"pinned_message,omitempty" yaml:"pinned_message,omitempty"`
+	Permissions   *ChatPermissions     `json:"permissions,omitempty" yaml:"permissions,omitempty"`
+
+	// Administrators are promoted with exactly the listed rights. Administrators missing from the list
+	// are left alone unless RemoveUnlistedAdministrators is set. The owner is never changed.
+	Administrators               []AdministratorConfig `json:"administrators,omitempty" yaml:"administrators,omitempty"`
+	RemoveUnlistedAdministrators bool                  `json:"remove_unlisted_administrators,omitempty" yaml:"remove_unlisted_administrators,omitempty"`
+}
+
+// PinnedMessageConfig is a message that must be pinned. When the pinned message has different text,
+// a new message is sent and pinned silently.
+type PinnedMessageConfig struct {
+	Text      string `json:"text" yaml:"text"`
+	ParseMode string `json:"parse_mode,omitempty" yaml:"parse_mode,omitempty"`
+}
+
+type AdministratorConfig struct {
+	UserId int64 `json:"user_id" yaml:"user_id"`
+	// CustomTitle is left as it is when empty.
+	CustomTitle string                  `json:"custom_title,omitempty" yaml:"custom_title,omitempty"`
+	Rights      ChatAdministratorRights `json:"rights" yaml:"rights"`
+}
+
+// LoadChannelsConfig reads a config file, as YAML when it has a .yaml or .yml extension and
+// as JSON otherwise. Photo paths are resolved against the directory of the file.
+func LoadChannelsConfig(path string) ([]ChannelConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	unmarshal := json.Unmarshal
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		unmarshal = yaml.Unmarshal
+	}
+
+	var config ChannelsConfig
+	if err := unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("channel config %s: %w", path, err)
+	}
+
+	for i, channel := range config.Channels {
+		if len(channel.Photo) > 0 && !filepath.IsAbs(channel.Photo) {
+			config.Channels[i].Photo = filepath.Join(filepath.Dir(path), channel.Photo)
+		}
+	}
+
+	return config.Channels, nil
+}
+
+// Channel config settings, used as ChannelChange.Setting.
+const (
+	ChannelSettingTitle         = "title"
+	ChannelSettingDescription   = "description"
+	ChannelSettingPhoto         = "photo"
+	ChannelSettingPermissions   = "permissions"
+	ChannelSettingAdministrator = "administrator"
+	ChannelSettingPinnedMessage = "pinned_message"
+)
+
+// ChannelChange is one difference between the config and the live chat.
+type ChannelChange struct {
+	ChatId  string
+	Setting string
+	From    string
+	To      string
+
+	apply func() error
+}
+
+func (c ChannelChange) String() string {
+	return fmt.Sprintf("%s: %s %q -> %q", c.ChatId, c.Setting, c.From, c.To)
+}
+
+// ChannelPlan lists the changes needed to bring the chats in line with their config.
+type ChannelPlan struct {
+	Changes []ChannelChange
+}
+
+func (p *ChannelPlan) String() string {
+	if len(p.Changes) == 0 {
+		return "no changes"
+	}
+
+	lines := make([]string, len(p.Changes))
+	for i, change := range p.Changes {
+		lines[i] = change.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ChannelReconciler compares channel configs with the live chats and applies the differences.
+// The bot must be an administrator of every chat with the rights the managed settings require.
+//
+// Telegram does not return the original chat photo, so the reconciler remembers which photo file
+// it uploaded in Storage. Without Storage a photo is only set when the chat has none.
+type ChannelReconciler struct {
+	Client  Telegram
+	Storage Storage
+	// Prefix is prepended to every storage key.
+	Prefix string
+}
+
+func NewChannelReconciler(client Telegram, storage Storage) *ChannelReconciler {
+	return &ChannelReconciler{Client: client, Storage: storage, Prefix: "channel_config:"}
+}
+
+// Sync plans the changes for configs and applies them unless dryRun is set. The plan is returned
+// in both cases; on an apply error it holds every planned change, including the ones not applied.
+func (r *ChannelReconciler) Sync(configs []ChannelConfig, dryRun bool) (*ChannelPlan, error) {
+	plan, err := r.Plan(configs)
+	if err != nil || dryRun {
+		return plan, err
+	}
+
+	return plan, r.Apply(plan)
+}
+
+// Plan reads the live state of every configured chat and returns the changes to apply.
+func (r *ChannelReconciler) Plan(configs []ChannelConfig) (*ChannelPlan, error) {
+	plan := &ChannelPlan{}
+	for _, config := range configs {
+		changes, err := r.planChannel(config)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %w", config.ChatId, err)
+		}
+
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	return plan, nil
+}
+
+// Apply applies the changes of plan in order and stops at the first error.
+func (r *ChannelReconciler) Apply(plan *ChannelPlan) error {
+	for _, change := range plan.Changes {
+		if err := change.apply(); err != nil {
+			return fmt.Errorf("%s: %w", change, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *ChannelReconciler) planChannel(config ChannelConfig) ([]ChannelChange, error) {
+	response, err := r.Client.GetChat(config.ChatId)
+	if err != nil {
+		return nil, err
+	}
+
+	chat := response.GetChat
+	chatId := config.ChatId
+
+	var changes []ChannelChange
+	add := func(setting, from, to string, apply func() error) {
+		changes = append(changes, ChannelChange{ChatId: chatId, Setting: setting, From: from, To: to, apply: apply})
+	}
+
+	if len(config.Title) > 0 && config.Title != chat.Title {
+		add(ChannelSettingTitle, chat.Title, config.Title, func() error {
+			return r.Client.SetChatTitle(chatId, config.Title)
+		})
+	}
+
+	if config.Description != nil && *config.Description != chat.Description {
+		description := *config.Description
+		add(ChannelSettingDescription, chat.Description, description, func() error {
+			return r.Client.SetChatDescription(chatId, description)
+		})
+	}
+
+	if len(config.Photo) > 0 {
+		change, err := r.planPhoto(chatId, config.Photo, chat.Photo)
+		if err != nil {
+			return nil, err
+		}
+
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+
+	if config.Permissions != nil && (chat.Permissions == nil || *chat.Permissions != *config.Permissions) {
+		permissions := *config.Permissions
+		add(ChannelSettingPermissions, marshalSetting(chat.Permissions), marshalSetting(permissions), func() error {
+			return r.Client.SetChatPermissions(SetChatPermissionsRequest{ChatId: chatId, Permissions: permissions})
+		})
+	}
+
+	if len(config.Administrators) > 0 || config.RemoveUnlistedAdministrators {
+		administratorChanges, err := r.planAdministrators(config)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, administratorChanges...)
+	}
+
+	if config.PinnedMessage != nil {
+		text, entities, err := Parse(config.PinnedMessage.ParseMode, config.PinnedMessage.Text)
+		if err != nil {
+			return nil, fmt.Errorf("pinned message: %w", err)
+		}
+
+		var pinnedText string
+		var pinnedEntities []MessageEntity
+		if chat.PinnedMessage != nil {
+			pinnedText, pinnedEntities = chat.PinnedMessage.Text, formattingEntities(chat.PinnedMessage.Entities)
+		}
+
+		if text != pinnedText || !sameEntities(formattingEntities(entities), pinnedEntities) {
+			add(ChannelSettingPinnedMessage, pinnedText, text, func() error {
+				sent, err := r.Client.SendMessage(MessageRequest{ChatId: chatId, Text: text, Entities: entities, DisableNotification: true})
+				if err != nil {
+					return err
+				}
+
+				return r.Client.PinChatMessage(chatId, sent.Result.MessageId, true)
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+type channelPhotoState struct {
+	SHA256       string `json:"sha256"`
+	FileUniqueId string `json:"file_unique_id"`
+}
+
+func (r *ChannelReconciler) planPhoto(chatId, path string, current *ChatPhoto) (*ChannelChange, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	key := r.Prefix + "photo:" + chatId
+
+	if current != nil {
+		if r.Storage == nil {
+			return nil, nil
+		}
+
+		stored, err := r.Storage.Get(key)
+		if err != nil && !errors.Is(err, ErrStorageNotFound) {
+			return nil, err
+		}
+
+		var state channelPhotoState
+		if stored != nil {
+			if err := json.Unmarshal(stored, &state); err != nil {
+				return nil, err
+			}
+		}
+
+		if state.SHA256 == digest && state.FileUniqueId == current.BigFileUniqueId {
+			return nil, nil
+		}
+	}
+
+	from := ""
+	if current != nil {
+		from = current.BigFileUniqueId
+	}
+
+	return &ChannelChange{ChatId: chatId, Setting: ChannelSettingPhoto, From: from, To: path, apply: func() error {
+		if err := r.Client.SetChatPhoto(chatId, base64.StdEncoding.EncodeToString(data)); err != nil {
+			return err
+		}
+
+		if r.Storage == nil {
+			return nil
+		}
+
+		response, err := r.Client.GetChat(chatId)
+		if err != nil {
+			return err
+		}
+
+		state := channelPhotoState{SHA256: digest}
+		if response.GetChat.Photo != nil {
+			state.FileUniqueId = response.GetChat.Photo.BigFileUniqueId
+		}
+
+		value, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+
+		return r.Storage.Set(key, value, 0)
+	}}, nil
+}
+
+func (r *ChannelReconciler) planAdministrators(config ChannelConfig) ([]ChannelChange, error) {
+	chatId := config.ChatId
+
+	administrators, err := r.Client.GetChatAdministrators(chatId)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[int64]NewChatMember)
+	for _, administrator := range administrators {
+		current[administrator.User.Id] = administrator
+	}
+
+	var changes []ChannelChange
+	listed := make(map[int64]bool)
+
+	for _, want := range config.Administrators {
+		listed[want.UserId] = true
+
+		have, ok := current[want.UserId]
+		sameTitle := len(want.CustomTitle) == 0 || have.CustomTitle == want.CustomTitle
+		if ok && (have.Status == ChatMemberStatusCreator || have.AdministratorRights() == want.Rights && sameTitle) {
+			continue
+		}
+
+		from := ""
+		if ok {
+			from = marshalSetting(have.AdministratorRights())
+		}
+
+		want := want
+		changes = append(changes, ChannelChange{
+			ChatId:  chatId,
+			Setting: ChannelSettingAdministrator + " " + strconv.FormatInt(want.UserId, 10),
+			From:    from,
+			To:      marshalSetting(want.Rights),
+			apply: func() error {
+				err := r.Client.PromoteChatMember(PromoteChatMemberRequest{ChatId: chatId, UserId: want.UserId, ChatAdministratorRights: want.Rights})
+				if err != nil || len(want.CustomTitle) == 0 {
+					return err
+				}
+
+				return r.Client.SetChatAdministratorCustomTitle(chatId, want.UserId, want.CustomTitle)
+			},
+		})
+	}
+
+	if !config.RemoveUnlistedAdministrators {
+		return changes, nil
+	}
+
+	for _, administrator := range administrators {
+		if listed[administrator.User.Id] || administrator.Status == ChatMemberStatusCreator || administrator.User.IsBot {
+			continue
+		}
+
+		userId := administrator.User.Id
+		changes = append(changes, ChannelChange{
+			ChatId:  chatId,
+			Setting: ChannelSettingAdministrator + " " + strconv.FormatInt(userId, 10),
+			From:    marshalSetting(administrator.AdministratorRights()),
+			apply: func() error {
+				return r.Client.PromoteChatMember(PromoteChatMemberRequest{ChatId: chatId, UserId: userId})
+			},
+		})
+	}
+
+	return changes, nil
+}
+
+// formattingEntities drops the entities Telegram detects by itself, such as URLs and mentions,
+// which are not part of the formatting a config can describe.
+func formattingEntities(entities []MessageEntity) []MessageEntity {
+	var formatting []MessageEntity
+	for _, entity := range entities {
+		switch entity.Type {
+		case EntityTypeMention, EntityTypeHashtag, EntityTypeCashtag, EntityTypeBotCommand,
+			EntityTypeURL, EntityTypeEmail, EntityTypePhoneNumber:
+			continue
+		}
+
+		formatting = append(formatting, entity)
+	}
+
+	return formatting
+}
+
+// entityKey is the part of a MessageEntity that affects formatting.
+type entityKey struct {
+	Type          string
+	Offset        int
+	Length        int
+	URL           string
+	UserId        int64
+	Language      string
+	CustomEmojiId string
+}
+
+// sameEntities reports whether two entity lists format a text the same way. Telegram may return
+// entities in another order, split overlapping ones and fill text_mention with the whole user,
+// so both lists are normalized first.
+func sameEntities(a, b []MessageEntity) bool {
+	x, y := normalizeEntities(a), normalizeEntities(b)
+	if len(x) != len(y) {
+		return false
+	}
+
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// normalizeEntities merges touching or overlapping entities of the same kind and sorts them
+// by offset, type and length.
+func normalizeEntities(entities []MessageEntity) []entityKey {
+	keys := make([]entityKey, 0, len(entities))
+	for _, entity := range entities {
+		key := entityKey{
+			Type:          entity.Type,
+			Offset:        entity.Offset,
+			Length:        entity.Length,
+			URL:           entity.URL,
+			Language:      entity.Language,
+			CustomEmojiId: entity.CustomEmojiId,
+		}
+		if entity.User != nil {
+			key.UserId = entity.User.Id
+		}
+
+		keys = append(keys, key)
+	}
+
+	sameKind := func(a, b entityKey) bool {
+		a.Offset, a.Length, b.Offset, b.Length = 0, 0, 0, 0
+
+		return a == b
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if !sameKind(a, b) {
+			return entityKeyLess(a, b)
+		}
+
+		return a.Offset < b.Offset
+	})
+
+	merged := keys[:0]
+	for _, key := range keys {
+		if n := len(merged); n > 0 && sameKind(merged[n-1], key) && key.Offset <= merged[n-1].Offset+merged[n-1].Length {
+			if end := key.Offset + key.Length; end > merged[n-1].Offset+merged[n-1].Length {
+				merged[n-1].Length = end - merged[n-1].Offset
+			}
+
+			continue
+		}
+
+		merged = append(merged, key)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		a, b := merged[i], merged[j]
+		if a.Offset != b.Offset {
+			return a.Offset < b.Offset
+		}
+
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+
+		if a.Length != b.Length {
+			return a.Length < b.Length
+		}
+
+		return entityKeyLess(a, b)
+	})
+
+	return merged
+}
+
+func entityKeyLess(a, b entityKey) bool {
+	switch {
+	case a.Type != b.Type:
+		return a.Type < b.Type
+	case a.URL != b.URL:
+		return a.URL < b.URL
+	case a.UserId != b.UserId:
+		return a.UserId < b.UserId
+	case a.Language != b.Language:
+		return a.Language < b.Language
+	case a.CustomEmojiId != b.CustomEmojiId:
+		return a.CustomEmojiId < b.CustomEmojiId
+	}
+
+	return false
+}
+
+func marshalSetting(value interface{}) string {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return ""
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(data)
+}
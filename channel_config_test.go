@@ -0,0 +1,205 @@
+package teledau
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChannelReconciler(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "logo.jpg"), []byte("jpeg"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	configPath := filepath.Join(dir, "channels.json")
+	err := os.WriteFile(configPath, []byte(`{"channels": [{
+		"chat_id": "@club",
+		"title": "Club",
+		"description": "",
+		"photo": "logo.jpg",
+		"permissions": {"can_send_messages": true},
+		"pinned_message": {"text": "*Rules*", "parse_mode": "MarkdownV2"},
+		"administrators": [
+			{"user_id": 1, "rights": {"can_delete_messages": true}},
+			{"user_id": 2, "custom_title": "mod", "rights": {"can_pin_messages": true}}
+		],
+		"remove_unlisted_administrators": true
+	}]}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configs, err := LoadChannelsConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, transport := newFakeTelegramClient(map[string]string{
+		"getChat": `{"ok":true,"result":{"id":-100,"title":"Old club","description":"old","permissions":{"can_send_messages":true},
+			"pinned_message":{"message_id":3,"text":"Rules https://example.com","entities":[{"type":"bold","offset":0,"length":5},{"type":"url","offset":6,"length":19}]}}}`,
+		"getChatAdministrators": `{"ok":true,"result":[
+			{"user":{"id":1},"status":"administrator","can_delete_messages":true},
+			{"user":{"id":3},"status":"administrator","can_pin_messages":true},
+			{"user":{"id":9},"status":"creator"}]}`,
+		"sendMessage": `{"ok":true,"result":{"message_id":4}}`,
+	})
+
+	reconciler := NewChannelReconciler(client, NewMemoryStorage())
+
+	plan, err := reconciler.Sync(configs, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var settings []string
+	for _, change := range plan.Changes {
+		settings = append(settings, change.Setting)
+	}
+
+	want := "title,description,photo,administrator 2,administrator 3,pinned_message"
+	if got := strings.Join(settings, ","); got != want {
+		t.Fatalf("planned %s, want %s\n%s", got, want, plan)
+	}
+
+	if len(transport.calls("setChatTitle")) != 0 {
+		t.Fatal("dry run must not change the chat")
+	}
+
+	if err := reconciler.Apply(plan); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := string(transport.calls("setChatTitle")[0].Body); body != `{"chat_id":"@club","title":"Club"}` {
+		t.Errorf("setChatTitle request = %s", body)
+	}
+
+	if form := readMultipartForm(t, transport.calls("setChatPhoto")[0]); form[TgFieldMediaType] != "jpeg" {
+		t.Errorf("unexpected setChatPhoto form: %v", form)
+	}
+
+	promotions := transport.calls("promoteChatMember")
+	if len(promotions) != 2 || string(promotions[1].Body) != `{"chat_id":"@club","user_id":3}` {
+		t.Errorf("unexpected promotions: %v", promotions)
+	}
+
+	if body := string(transport.calls("setChatAdministratorCustomTitle")[0].Body); !strings.Contains(body, `"custom_title":"mod"`) {
+		t.Errorf("setChatAdministratorCustomTitle request = %s", body)
+	}
+
+	if body := string(transport.calls("pinChatMessage")[0].Body); body != `{"chat_id":"@club","message_id":4,"disable_notification":true}` {
+		t.Errorf("pinChatMessage request = %s", body)
+	}
+}
+
+func TestChannelReconcilerPhotoState(t *testing.T) {
+	photo := filepath.Join(t.TempDir(), "logo.jpg")
+	if err := os.WriteFile(photo, []byte("jpeg"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	client, transport := newFakeTelegramClient(map[string]string{
+		"getChat": `{"ok":true,"result":{"id":-100,"photo":{"big_file_unique_id":"big-1"}}}`,
+	})
+
+	reconciler := NewChannelReconciler(client, NewMemoryStorage())
+	configs := []ChannelConfig{{ChatId: "@club", Photo: photo}}
+
+	if _, err := reconciler.Sync(configs, false); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := reconciler.Plan(configs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Changes) != 0 || len(transport.calls("setChatPhoto")) != 1 {
+		t.Errorf("photo should be uploaded once, plan after sync: %s", plan)
+	}
+}
+
+func TestChannelReconcilerConverges(t *testing.T) {
+	client, _ := newFakeTelegramClient(map[string]string{
+		"getChat": `{"ok":true,"result":{"id":-100,"title":"Club","pinned_message":{"message_id":3,"text":"Rules by Ann",
+			"entities":[{"type":"italic","offset":0,"length":5},{"type":"bold","offset":0,"length":3},{"type":"bold","offset":3,"length":2},
+			{"type":"text_mention","offset":9,"length":3,"user":{"id":7,"is_bot":false,"first_name":"Ann"}}]}}}`,
+		"getChatAdministrators": `{"ok":true,"result":[{"user":{"id":1},"status":"administrator","custom_title":"boss","can_delete_messages":true}]}`,
+	})
+
+	configs := []ChannelConfig{{
+		ChatId: "@club",
+		PinnedMessage: &PinnedMessageConfig{
+			Text:      "*_Rules_* by [Ann](tg://user?id=7)",
+			ParseMode: "MarkdownV2",
+		},
+		Administrators: []AdministratorConfig{{UserId: 1, Rights: ChatAdministratorRights{CanDeleteMessages: true}}},
+	}}
+
+	plan, err := NewChannelReconciler(client, NewMemoryStorage()).Sync(configs, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Changes) != 0 {
+		t.Fatalf("nothing should change, planned:\n%s", plan)
+	}
+}
+
+func TestLoadChannelsConfigYAML(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"channels.json": `{"channels": [{
+			"chat_id": "@club",
+			"description": "",
+			"photo": "logo.jpg",
+			"permissions": {"can_send_messages": true, "can_send_polls": true},
+			"pinned_message": {"text": "*Rules*", "parse_mode": "MarkdownV2"},
+			"administrators": [{"user_id": 2, "custom_title": "mod", "rights": {"can_pin_messages": true}}],
+			"remove_unlisted_administrators": true
+		}]}`,
+		"channels.yml": `channels:
+  - chat_id: "@club"
+    description: ""
+    photo: logo.jpg
+    permissions:
+      can_send_messages: true
+      can_send_polls: true
+    pinned_message:
+      text: "*Rules*"
+      parse_mode: MarkdownV2
+    administrators:
+      - user_id: 2
+        custom_title: mod
+        rights:
+          can_pin_messages: true
+    remove_unlisted_administrators: true
+`,
+	}
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := LoadChannelsConfig(filepath.Join(dir, "channels.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadChannelsConfig(filepath.Join(dir, "channels.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("YAML config = %+v, want %+v", got, want)
+	}
+
+	if got[0].Description == nil || got[0].Photo != filepath.Join(dir, "logo.jpg") {
+		t.Fatalf("unexpected description %v or photo %q", got[0].Description, got[0].Photo)
+	}
+}
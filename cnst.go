@@ -5,34 +5,228 @@ const (
 
 	HeaderContentType = "Content-Type"
 
-	TgBotBaseUrl               = "https://api.telegram.org/bot"
-	TgBotGetChat               = "/getChat?chat_id="
-	TgBotSendMessageUrl        = "/sendMessage"
-	TgBotEditMessageUrl        = "/editMessageText"
-	TgBotEditCaptionUrl        = "/editMessageCaption"
-	TgBotSendPoolUrl           = "/sendPoll"
-	TgBotForwardMsgUrl         = "/forwardMessage"
-	TgBotDeleteMsgUrl          = "/deleteMessage"
-	TgBotSendStickerUrl        = "/sendSticker"
-	TgBotCreateInviteLinkUrl   = "/createChatInviteLink"
-	TgBotSendPhotoUrlSptf      = "https://api.telegram.org/bot%s/sendPhoto?chat_id=%s"
-	TgBotSendMediaGroupUrlSptf = "https://api.telegram.org/bot%s/sendMediaGroup?chat_id=%s"
-	TgBotDownloadFileUrl       = "https://api.telegram.org/file/bot%s/%s"
-	TgBotGetFileUrl            = "https://api.telegram.org/bot%s/getFile?file_id=%s"
+	TgBotBaseUrl                              = "https://api.telegram.org/bot"
+	TgBotGetChat                              = "/getChat?chat_id="
+	TgBotSendMessageUrl                       = "/sendMessage"
+	TgBotEditMessageUrl                       = "/editMessageText"
+	TgBotEditCaptionUrl                       = "/editMessageCaption"
+	TgBotSendPoolUrl                          = "/sendPoll"
+	TgBotForwardMsgUrl                        = "/forwardMessage"
+	TgBotDeleteMsgUrl                         = "/deleteMessage"
+	TgBotSendStickerUrl                       = "/sendSticker"
+	TgBotCreateInviteLinkUrl                  = "/createChatInviteLink"
+	TgBotAnswerInlineQueryUrl                 = "/answerInlineQuery"
+	TgBotSendPhotoUrl                         = "/sendPhoto"
+	TgBotSendMediaGroupUrl                    = "/sendMediaGroup"
+	TgBotCreateForumTopicUrl                  = "/createForumTopic"
+	TgBotEditForumTopicUrl                    = "/editForumTopic"
+	TgBotCloseForumTopicUrl                   = "/closeForumTopic"
+	TgBotReopenForumTopicUrl                  = "/reopenForumTopic"
+	TgBotDeleteForumTopicUrl                  = "/deleteForumTopic"
+	TgBotUnpinAllForumTopicMessagesUrl        = "/unpinAllForumTopicMessages"
+	TgBotEditGeneralForumTopicUrl             = "/editGeneralForumTopic"
+	TgBotCloseGeneralForumTopicUrl            = "/closeGeneralForumTopic"
+	TgBotReopenGeneralForumTopicUrl           = "/reopenGeneralForumTopic"
+	TgBotHideGeneralForumTopicUrl             = "/hideGeneralForumTopic"
+	TgBotUnhideGeneralForumTopicUrl           = "/unhideGeneralForumTopic"
+	TgBotUnpinAllGeneralForumTopicMessagesUrl = "/unpinAllGeneralForumTopicMessages"
+	TgBotGetForumTopicIconStickersUrl         = "/getForumTopicIconStickers"
+	TgBotBanChatMemberUrl                     = "/banChatMember"
+	TgBotUnbanChatMemberUrl                   = "/unbanChatMember"
+	TgBotRestrictChatMemberUrl                = "/restrictChatMember"
+	TgBotPromoteChatMemberUrl                 = "/promoteChatMember"
+	TgBotSetChatPermissionsUrl                = "/setChatPermissions"
+	TgBotSetChatAdministratorCustomTitleUrl   = "/setChatAdministratorCustomTitle"
+	TgBotBanChatSenderChatUrl                 = "/banChatSenderChat"
+	TgBotUnbanChatSenderChatUrl               = "/unbanChatSenderChat"
+	TgBotGetChatAdministratorsUrl             = "/getChatAdministrators"
+	TgBotGetChatMemberUrl                     = "/getChatMember"
+	TgBotGetChatMemberCountUrl                = "/getChatMemberCount"
+	TgBotEditChatInviteLinkUrl                = "/editChatInviteLink"
+	TgBotCreateChatSubscriptionInviteLinkUrl  = "/createChatSubscriptionInviteLink"
+	TgBotEditChatSubscriptionInviteLinkUrl    = "/editChatSubscriptionInviteLink"
+	TgBotRevokeChatInviteLinkUrl              = "/revokeChatInviteLink"
+	TgBotExportChatInviteLinkUrl              = "/exportChatInviteLink"
+	TgBotApproveChatJoinRequestUrl            = "/approveChatJoinRequest"
+	TgBotDeclineChatJoinRequestUrl            = "/declineChatJoinRequest"
+	TgBotSetChatTitleUrl                      = "/setChatTitle"
+	TgBotSetChatDescriptionUrl                = "/setChatDescription"
+	TgBotSetChatPhotoUrl                      = "/setChatPhoto"
+	TgBotDeleteChatPhotoUrl                   = "/deleteChatPhoto"
+	TgBotPinChatMessageUrl                    = "/pinChatMessage"
+	TgBotUnpinChatMessageUrl                  = "/unpinChatMessage"
+	TgBotUnpinAllChatMessagesUrl              = "/unpinAllChatMessages"
+	TgBotLeaveChatUrl                         = "/leaveChat"
+	TgBotEditMessageMediaUrl                  = "/editMessageMedia"
+	TgBotDeleteMessagesUrl                    = "/deleteMessages"
+	TgBotForwardMessagesUrl                   = "/forwardMessages"
+	TgBotCopyMessageUrl                       = "/copyMessage"
+	TgBotCopyMessagesUrl                      = "/copyMessages"
+	TgBotEditMessageReplyMarkupUrl            = "/editMessageReplyMarkup"
+	TgBotEditMessageLiveLocationUrl           = "/editMessageLiveLocation"
+	TgBotStopMessageLiveLocationUrl           = "/stopMessageLiveLocation"
+	TgBotStopPollUrl                          = "/stopPoll"
+	TgBotGetStickerSetUrl                     = "/getStickerSet"
+	TgBotGetCustomEmojiStickersUrl            = "/getCustomEmojiStickers"
+	TgBotUploadStickerFileUrl                 = "/uploadStickerFile"
+	TgBotCreateNewStickerSetUrl               = "/createNewStickerSet"
+	TgBotAddStickerToSetUrl                   = "/addStickerToSet"
+	TgBotSetStickerPositionInSetUrl           = "/setStickerPositionInSet"
+	TgBotDeleteStickerFromSetUrl              = "/deleteStickerFromSet"
+	TgBotReplaceStickerInSetUrl               = "/replaceStickerInSet"
+	TgBotSetStickerEmojiListUrl               = "/setStickerEmojiList"
+	TgBotSetStickerSetThumbnailUrl            = "/setStickerSetThumbnail"
+	TgBotSetCustomEmojiStickerSetThumbnailUrl = "/setCustomEmojiStickerSetThumbnail"
+	TgBotSendPhotoUrlSptf                     = "https://api.telegram.org/bot%s/sendPhoto?chat_id=%s"
+	TgBotSendMediaGroupUrlSptf                = "https://api.telegram.org/bot%s/sendMediaGroup?chat_id=%s"
+	TgBotDownloadFileUrl                      = "https://api.telegram.org/file/bot%s/%s"
+	TgBotGetFileUrl                           = "https://api.telegram.org/bot%s/getFile?file_id=%s"
 
 	TgParseModMarkdownHTML = "HTML"
 	TgParseModMarkdownV1   = "Markdown"
 	TgParseModMarkdownV2   = "MarkdownV2"
 
-	TgFieldNameParseMod = "parse_mode"
-	TgFieldCaption      = "caption"
-	TgFieldChatId       = "chat_id"
-	TgFieldSticker      = "sticker"
-	TgFieldMediaType    = "photo"
-	TgFieldMessageId    = "message_id"
-	TgFieldFromChatId   = "from_chat_id"
+	TgFieldNameParseMod    = "parse_mode"
+	TgFieldCaption         = "caption"
+	TgFieldCaptionEntities = "caption_entities"
+	TgFieldMedia           = "media"
+	TgFieldChatId          = "chat_id"
+	TgFieldSticker         = "sticker"
+	TgFieldMediaType       = "photo"
+	TgFieldMessageId       = "message_id"
+	TgFieldFromChatId      = "from_chat_id"
+	TgFieldEmoji           = "emoji"
+	TgFieldReplyMarkup     = "reply_markup"
+	TgFieldInlineMessageId = "inline_message_id"
+	TgFieldUserId          = "user_id"
+	TgFieldName            = "name"
+	TgFieldStickers        = "stickers"
+	TgFieldThumbnail       = "thumbnail"
+	TgFieldFormat          = "format"
+	TgFieldTitle           = "title"
+	TgFieldStickerType     = "sticker_type"
+	TgFieldNeedsRepainting = "needs_repainting"
+	TgFieldOldSticker      = "old_sticker"
 
-	TempFileName        = "image*.jpeg"
+	TgFieldMessageThreadId     = "message_thread_id"
+	TgFieldDisableNotification = "disable_notification"
+	TgFieldProtectContent      = "protect_content"
+	TgFieldMessageEffectId     = "message_effect_id"
+	TgFieldReplyParameters     = "reply_parameters"
+
+	PollTypeRegular = "regular"
+	PollTypeQuiz    = "quiz"
+
+	ChatTypePrivate    = "private"
+	ChatTypeGroup      = "group"
+	ChatTypeSupergroup = "supergroup"
+	ChatTypeChannel    = "channel"
+
+	StickerFormatStatic   = "static"
+	StickerFormatAnimated = "animated"
+	StickerFormatVideo    = "video"
+
+	StickerTypeRegular     = "regular"
+	StickerTypeMask        = "mask"
+	StickerTypeCustomEmoji = "custom_emoji"
+
+	// StickerImage* are the encodings PrepareSticker produces.
+	StickerImagePNG  = "png"
+	StickerImageWebP = "webp"
+
+	MediaTypePhoto = "photo"
+	MediaTypeVideo = "video"
+
+	InlineResultTypeArticle  = "article"
+	InlineResultTypePhoto    = "photo"
+	InlineResultTypeGif      = "gif"
+	InlineResultTypeVideo    = "video"
+	InlineResultTypeDocument = "document"
+	InlineResultTypeSticker  = "sticker"
+
+	EntityTypeMention              = "mention"
+	EntityTypeHashtag              = "hashtag"
+	EntityTypeCashtag              = "cashtag"
+	EntityTypeBotCommand           = "bot_command"
+	EntityTypeURL                  = "url"
+	EntityTypeEmail                = "email"
+	EntityTypePhoneNumber          = "phone_number"
+	EntityTypeBold                 = "bold"
+	EntityTypeItalic               = "italic"
+	EntityTypeUnderline            = "underline"
+	EntityTypeStrikethrough        = "strikethrough"
+	EntityTypeSpoiler              = "spoiler"
+	EntityTypeBlockquote           = "blockquote"
+	EntityTypeExpandableBlockquote = "expandable_blockquote"
+	EntityTypeCode                 = "code"
+	EntityTypePre                  = "pre"
+	EntityTypeTextLink             = "text_link"
+	EntityTypeTextMention          = "text_mention"
+	EntityTypeCustomEmoji          = "custom_emoji"
+
+	UpdateTypeMessage              = "message"
+	UpdateTypeEditedMessage        = "edited_message"
+	UpdateTypeChannelPost          = "channel_post"
+	UpdateTypeEditedChannelPost    = "edited_channel_post"
+	UpdateTypeBusinessMessage      = "business_message"
+	UpdateTypeChatMember           = "chat_member"
+	UpdateTypeMyChatMember         = "my_chat_member"
+	UpdateTypeChatJoinRequest      = "chat_join_request"
+	UpdateTypeInlineQuery          = "inline_query"
+	UpdateTypeChosenInlineResult   = "chosen_inline_result"
+	UpdateTypePoll                 = "poll"
+	UpdateTypePollAnswer           = "poll_answer"
+	UpdateTypeMessageReaction      = "message_reaction"
+	UpdateTypeMessageReactionCount = "message_reaction_count"
+	UpdateTypeShippingQuery        = "shipping_query"
+	UpdateTypePreCheckoutQuery     = "pre_checkout_query"
+
+	MaxMessageLength = 4096
+	MaxCaptionLength = 1024
+	// MaxBulkMessages is the number of messages the Bot API forwards, copies or deletes in one request.
+	MaxBulkMessages = 100
+	// StickerSize is the length of the longer side of a static sticker.
+	StickerSize = 512
+	// MaxStickerFileSize is the largest static sticker file the Bot API accepts.
+	MaxStickerFileSize = 512 * 1024
+	// MaxStickerSourcePixels limits the images PrepareSticker decodes, e.g. 8000x6000.
+	MaxStickerSourcePixels = 48_000_000
+
+	ChatMemberStatusCreator       = "creator"
+	ChatMemberStatusAdministrator = "administrator"
+	ChatMemberStatusMember        = "member"
+	ChatMemberStatusRestricted    = "restricted"
+	ChatMemberStatusLeft          = "left"
+	ChatMemberStatusKicked        = "kicked"
+
+	SubscriptionPeriod30Days = 2592000
+
+	// PostTypeAuto picks the post type from the number of images.
+	PostTypeAuto       = 0
+	PostTypeText       = 1
+	PostTypePhoto      = 2
+	PostTypeMediaGroup = 3
+
+	PostStatusQueued    = 0
+	PostStatusPublished = 1
+	PostStatusFailed    = 2
+	PostStatusCanceled  = 3
+
+	ForumTopicIconColorBlue   = 0x6FB9F0
+	ForumTopicIconColorYellow = 0xFFD67E
+	ForumTopicIconColorViolet = 0xCB86DB
+	ForumTopicIconColorGreen  = 0x8EEE98
+	ForumTopicIconColorRose   = 0xFF93B2
+	ForumTopicIconColorRed    = 0xFB6F5F
+
+	TgPhotoFileName         = "image.jpeg"
+	TgChatPhotoFileName     = "chat_photo.jpeg"
+	TgPhotoGroupFileNameFmt = "image_%d.jpeg"
+	TgStickerFileName       = "sticker.webp"
+	TgStickerPNGFileName    = "sticker.png"
+
+	TempFileName = "image*.jpeg"
+	// Deprecated: stickers are uploaded as TgStickerFileName.
 	TempStickerFileName = "/path/to/decoded/sticker.webp"
 	TempFileNameFmt     = "image_%d_%d*.jpeg"
 )
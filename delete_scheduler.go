@@ -0,0 +1,204 @@
+package teledau
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+)
+
+// ScheduledDelete is a message waiting for DeleteScheduler.
+type ScheduledDelete struct {
+	ChatId    string `json:"chat_id"`
+	MessageId int    `json:"message_id"`
+	// DeleteAt is the RFC 3339 time the message is deleted at.
+	DeleteAt string `json:"delete_at"`
+}
+
+// DeleteScheduler deletes messages after a delay, e.g. for notices that should disappear
+// once read. Pending deletes are kept in Storage, so they survive restarts.
+//
+// Deletes the Bot API refuses, such as messages older than 48 hours or a lost right to delete,
+// are dropped with a log line; flood-control and server errors are retried on the next run.
+type DeleteScheduler struct {
+	Client  Telegram
+	Storage Storage
+	// Interval is how often Run checks for due deletes.
+	Interval time.Duration
+	// Prefix is prepended to every storage key.
+	Prefix string
+}
+
+func NewDeleteScheduler(client Telegram, storage Storage) *DeleteScheduler {
+	return &DeleteScheduler{Client: client, Storage: storage, Interval: 5 * time.Second, Prefix: "delete_scheduler:"}
+}
+
+// Schedule deletes the message at the given time.
+func (s *DeleteScheduler) Schedule(chatId string, messageId int, at time.Time) error {
+	return s.update(func(pending []ScheduledDelete) []ScheduledDelete {
+		return append(pending, ScheduledDelete{ChatId: chatId, MessageId: messageId, DeleteAt: at.UTC().Format(time.RFC3339)})
+	})
+}
+
+// SendMessage sends message and schedules it for deletion after ttl.
+func (s *DeleteScheduler) SendMessage(message MessageRequest, ttl time.Duration) (SendMessageResponse, error) {
+	response, err := s.Client.SendMessage(message)
+	if err != nil {
+		return response, err
+	}
+
+	return response, s.Schedule(message.ChatId, response.Result.MessageId, time.Now().Add(ttl))
+}
+
+// Pending returns the deletes that haven't been done yet.
+func (s *DeleteScheduler) Pending() ([]ScheduledDelete, error) {
+	data, err := s.Storage.Get(s.Prefix + "pending")
+	if errors.Is(err, ErrStorageNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []ScheduledDelete
+	if err := json.Unmarshal(data, &pending); err != nil {
+		return nil, err
+	}
+
+	return pending, nil
+}
+
+// Run deletes due messages every Interval until ctx is done.
+func (s *DeleteScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(time.Now()); err != nil {
+			log.Printf("Error deleting scheduled messages: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce deletes the messages due at now, one request per chat and MaxBulkMessages messages.
+// Only storage errors are returned.
+func (s *DeleteScheduler) RunOnce(now time.Time) error {
+	pending, err := s.Pending()
+	if err != nil {
+		return err
+	}
+
+	due := make(map[string][]int)
+	var chats []string
+	for _, d := range pending {
+		at, err := time.Parse(time.RFC3339, d.DeleteAt)
+		if err == nil && at.After(now) {
+			continue
+		}
+
+		if _, ok := due[d.ChatId]; !ok {
+			chats = append(chats, d.ChatId)
+		}
+
+		due[d.ChatId] = append(due[d.ChatId], d.MessageId)
+	}
+
+	if len(chats) == 0 {
+		return nil
+	}
+
+	done := make(map[string][]int)
+	for _, chatId := range chats {
+		for _, chunk := range chunkMessageIds(due[chatId]) {
+			deleted, retry := s.deleteChunk(chatId, chunk)
+			done[chatId] = append(done[chatId], deleted...)
+
+			if retry {
+				break
+			}
+		}
+	}
+
+	return s.update(func(pending []ScheduledDelete) []ScheduledDelete {
+		kept := pending[:0]
+		for _, d := range pending {
+			if containsInt(done[d.ChatId], d.MessageId) {
+				continue
+			}
+
+			kept = append(kept, d)
+		}
+
+		return kept
+	})
+}
+
+// deleteChunk deletes up to MaxBulkMessages messages of a chat and returns the ids that can be dropped:
+// the deleted ones and those the Bot API refused. When the batch is refused, the messages are deleted
+// one by one, so a single old message doesn't take the others with it. retry reports a temporary
+// error, after which the chat is left for the next run.
+func (s *DeleteScheduler) deleteChunk(chatId string, messageIds []int) (dropped []int, retry bool) {
+	err := s.Client.DeleteMessages(chatId, messageIds)
+	if err == nil {
+		return messageIds, false
+	}
+
+	if !permanentAPIError(err) {
+		log.Printf("Error deleting messages %v in %s: %v", messageIds, chatId, err)
+
+		return nil, true
+	}
+
+	for _, messageId := range messageIds {
+		err := s.Client.DeleteMessage(chatId, messageId)
+		if err != nil {
+			log.Printf("Error deleting message %d in %s: %v", messageId, chatId, err)
+
+			if !permanentAPIError(err) {
+				return dropped, true
+			}
+		}
+
+		dropped = append(dropped, messageId)
+	}
+
+	return dropped, false
+}
+
+func permanentAPIError(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && !apiErr.Temporary()
+}
+
+// update changes the pending deletes with a compare-and-swap loop, so Schedule may be called
+// while RunOnce is deleting.
+func (s *DeleteScheduler) update(change func(pending []ScheduledDelete) []ScheduledDelete) error {
+	return UpdateStorage(s.Storage, s.Prefix+"pending", 0, func(current []byte) ([]byte, error) {
+		var pending []ScheduledDelete
+		if current != nil {
+			if err := json.Unmarshal(current, &pending); err != nil {
+				return nil, err
+			}
+		}
+
+		return json.Marshal(change(pending))
+	})
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
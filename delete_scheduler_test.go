@@ -0,0 +1,108 @@
+package teledau
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestDeleteScheduler(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	transport.handler = func(method string, body []byte) (int, string) {
+		var request deleteMessagesRequest
+		_ = json.Unmarshal(body, &request)
+
+		switch request.ChatId {
+		case "@old":
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message can't be deleted"}`
+		case "@busy":
+			return http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`
+		}
+
+		return http.StatusOK, `{"ok":true,"result":true}`
+	}
+
+	scheduler := NewDeleteScheduler(client, NewMemoryStorage())
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, d := range []ScheduledDelete{{"@club", 1, ""}, {"@club", 2, ""}, {"@old", 3, ""}, {"@busy", 4, ""}} {
+		if err := scheduler.Schedule(d.ChatId, d.MessageId, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := scheduler.Schedule("@club", 5, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scheduler.RunOnce(now); err != nil {
+		t.Fatal(err)
+	}
+
+	var club deleteMessagesRequest
+	if err := json.Unmarshal(transport.calls("deleteMessages")[0].Body, &club); err != nil {
+		t.Fatal(err)
+	}
+
+	if club.ChatId != "@club" || len(club.MessageIds) != 2 {
+		t.Errorf("due messages of a chat should be deleted together, got %+v", club)
+	}
+
+	pending, err := scheduler.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != 2 || pending[0].ChatId != "@busy" || pending[1].MessageId != 5 {
+		t.Errorf("unexpected pending deletes: %+v", pending)
+	}
+}
+
+func TestDeleteScheduler_PartialFailures(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	transport.handler = func(method string, body []byte) (int, string) {
+		var request struct {
+			deleteMessagesRequest
+			MessageId int `json:"message_id"`
+		}
+		_ = json.Unmarshal(body, &request)
+
+		switch {
+		case method == "deleteMessages" && request.MessageIds[0] > MaxBulkMessages:
+			return http.StatusTooManyRequests, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 5","parameters":{"retry_after":5}}`
+		case method == "deleteMessages" && containsInt(request.MessageIds, 7), request.MessageId == 7:
+			return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message can't be deleted"}`
+		}
+
+		return http.StatusOK, `{"ok":true,"result":true}`
+	}
+
+	scheduler := NewDeleteScheduler(client, NewMemoryStorage())
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for id := 1; id <= 150; id++ {
+		if err := scheduler.Schedule("@club", id, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := scheduler.RunOnce(now); err != nil {
+		t.Fatal(err)
+	}
+
+	// The refused batch is retried one message at a time.
+	if calls := len(transport.calls("deleteMessage")); calls != MaxBulkMessages {
+		t.Errorf("deleteMessage was called %d times, want %d", calls, MaxBulkMessages)
+	}
+
+	pending, err := scheduler.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The second batch hit flood control and stays for the next run.
+	if len(pending) != 150-MaxBulkMessages || pending[0].MessageId != MaxBulkMessages+1 {
+		t.Errorf("unexpected pending deletes: %+v", pending)
+	}
+}
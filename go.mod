@@ -1,3 +1,8 @@
 module github.com/daulet140/teledau
 
 go 1.19
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.22
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,39 @@
+package teledau
+
+// InputFile is a file for the Bot API: either uploaded from Data, or referenced by FileId,
+// which may also be an HTTP URL for Telegram to download.
+type InputFile struct {
+	FileId string
+	Name   string
+	Data   []byte
+}
+
+// writeField sets field to the file_id of f, or returns f as the file to upload under field.
+func (f InputFile) writeField(fields map[string]string, field, defaultName string) []multipartFile {
+	if len(f.FileId) > 0 {
+		fields[field] = f.FileId
+
+		return nil
+	}
+
+	return []multipartFile{f.upload(field, defaultName)}
+}
+
+// attach returns the value referring to f inside a JSON field, like the media of InputMedia,
+// and the file to upload under field if f isn't a file_id.
+func (f InputFile) attach(field, defaultName string) (string, []multipartFile) {
+	if len(f.FileId) > 0 {
+		return f.FileId, nil
+	}
+
+	return "attach://" + field, []multipartFile{f.upload(field, defaultName)}
+}
+
+func (f InputFile) upload(field, defaultName string) multipartFile {
+	name := f.Name
+	if name == "" {
+		name = defaultName
+	}
+
+	return multipartFile{Field: field, Name: name, Data: f.Data}
+}
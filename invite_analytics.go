@@ -0,0 +1,211 @@
+package teledau
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const inviteAnalyticsDayFormat = "2006-01-02"
+
+// InviteAnalytics attributes joins and leaves of a chat to the invite link the user joined with,
+// so campaigns created with GenerateInviteLinks can be compared. Counters are kept per chat,
+// per day and per link name (InviteLink.Name, or the link itself when it has no name).
+// Joins without a link the bot can see are counted under an empty name.
+//
+// Feed it every chat_member update; the bot must be an administrator and request
+// UpdateTypeChatMember in allowed_updates to receive them.
+type InviteAnalytics struct {
+	Storage Storage
+	// Prefix is prepended to every storage key.
+	Prefix string
+	// Location sets the day boundaries; UTC when nil.
+	Location *time.Location
+}
+
+func NewInviteAnalytics(storage Storage) *InviteAnalytics {
+	return &InviteAnalytics{Storage: storage, Prefix: "invite_analytics:"}
+}
+
+// InviteStats are the counters of one link name.
+type InviteStats struct {
+	Name   string `json:"name"`
+	Joins  int    `json:"joins"`
+	Leaves int    `json:"leaves"`
+}
+
+// Net returns the subscribers the link brought and kept.
+func (s InviteStats) Net() int {
+	return s.Joins - s.Leaves
+}
+
+type InviteDayStats struct {
+	Day   string        `json:"day"`
+	Links []InviteStats `json:"links"`
+}
+
+// InviteReport holds the counters of a chat for a range of days. Totals sums them per link,
+// sorted by joins, most first.
+type InviteReport struct {
+	ChatId int64            `json:"chat_id"`
+	Days   []InviteDayStats `json:"days"`
+	Totals []InviteStats    `json:"totals"`
+}
+
+// HandleUpdate records u if it is a chat_member update and ignores it otherwise.
+func (a *InviteAnalytics) HandleUpdate(u Update) error {
+	if u.ChatMember == nil {
+		return nil
+	}
+
+	return a.Track(*u.ChatMember)
+}
+
+// Track records a status change. A join remembers the link the user came with,
+// so that a later leave is counted against the same link.
+func (a *InviteAnalytics) Track(m ChatMember) error {
+	wasIn, isIn := m.OldChatMember.IsInChat(), m.NewChatMember.IsInChat()
+	if wasIn == isIn {
+		return nil
+	}
+
+	chatId := int64(m.Chat.Id)
+	userKey := a.key("user:%d:%d", chatId, m.NewChatMember.User.Id)
+	day := time.Unix(int64(m.Date), 0).In(a.location()).Format(inviteAnalyticsDayFormat)
+
+	if isIn {
+		name := m.InviteLink.Name
+		if len(name) == 0 {
+			name = m.InviteLink.InviteLink
+		}
+
+		if err := a.Storage.Set(userKey, []byte(name), 0); err != nil {
+			return err
+		}
+
+		return a.increment(chatId, day, name, func(s *InviteStats) { s.Joins++ })
+	}
+
+	name, err := a.Storage.Get(userKey)
+	if err != nil && !errors.Is(err, ErrStorageNotFound) {
+		return err
+	}
+
+	if err := a.increment(chatId, day, string(name), func(s *InviteStats) { s.Leaves++ }); err != nil {
+		return err
+	}
+
+	return a.Storage.Delete(userKey)
+}
+
+// Report returns the counters of chatId for the days from..to, both included.
+// Days without joins or leaves are omitted.
+func (a *InviteAnalytics) Report(chatId int64, from, to time.Time) (*InviteReport, error) {
+	report := &InviteReport{ChatId: chatId}
+	totals := make(map[string]*InviteStats)
+
+	from, to = from.In(a.location()), to.In(a.location())
+	for day := dayStart(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		stats, err := a.day(chatId, day.Format(inviteAnalyticsDayFormat))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(stats) == 0 {
+			continue
+		}
+
+		dayStats := InviteDayStats{Day: day.Format(inviteAnalyticsDayFormat)}
+		for _, s := range stats {
+			dayStats.Links = append(dayStats.Links, *s)
+
+			total, ok := totals[s.Name]
+			if !ok {
+				total = &InviteStats{Name: s.Name}
+				totals[s.Name] = total
+			}
+
+			total.Joins += s.Joins
+			total.Leaves += s.Leaves
+		}
+
+		sortInviteStats(dayStats.Links)
+		report.Days = append(report.Days, dayStats)
+	}
+
+	for _, total := range totals {
+		report.Totals = append(report.Totals, *total)
+	}
+
+	sortInviteStats(report.Totals)
+
+	return report, nil
+}
+
+func (a *InviteAnalytics) increment(chatId int64, day, name string, update func(s *InviteStats)) error {
+	return UpdateStorage(a.Storage, a.key("day:%d:%s", chatId, day), 0, func(current []byte) ([]byte, error) {
+		stats := make(map[string]*InviteStats)
+		if current != nil {
+			if err := json.Unmarshal(current, &stats); err != nil {
+				return nil, err
+			}
+		}
+
+		s, ok := stats[name]
+		if !ok {
+			s = &InviteStats{Name: name}
+			stats[name] = s
+		}
+
+		update(s)
+
+		return json.Marshal(stats)
+	})
+}
+
+func (a *InviteAnalytics) day(chatId int64, day string) (map[string]*InviteStats, error) {
+	data, err := a.Storage.Get(a.key("day:%d:%s", chatId, day))
+	if errors.Is(err, ErrStorageNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]*InviteStats)
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (a *InviteAnalytics) key(format string, args ...interface{}) string {
+	return a.Prefix + fmt.Sprintf(format, args...)
+}
+
+func (a *InviteAnalytics) location() *time.Location {
+	if a.Location == nil {
+		return time.UTC
+	}
+
+	return a.Location
+}
+
+func dayStart(t time.Time) time.Time {
+	year, month, day := t.Date()
+
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func sortInviteStats(stats []InviteStats) {
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Joins != stats[j].Joins {
+			return stats[i].Joins > stats[j].Joins
+		}
+
+		return stats[i].Name < stats[j].Name
+	})
+}
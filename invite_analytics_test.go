@@ -0,0 +1,64 @@
+package teledau
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func chatMemberUpdate(userId int64, date time.Time, oldStatus, newStatus, link, name string) Update {
+	return Update{ChatMember: &ChatMember{
+		Chat:          Chat{Id: -100},
+		From:          From{Id: userId},
+		Date:          int(date.Unix()),
+		OldChatMember: NewChatMember{User: From{Id: userId}, Status: oldStatus},
+		NewChatMember: NewChatMember{User: From{Id: userId}, Status: newStatus},
+		InviteLink:    InviteLink{InviteLink: link, Name: name},
+	}}
+}
+
+func TestInviteAnalytics(t *testing.T) {
+	analytics := NewInviteAnalytics(NewMemoryStorage())
+
+	day1 := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	updates := []Update{
+		chatMemberUpdate(1, day1, ChatMemberStatusLeft, ChatMemberStatusMember, "https://t.me/+a", "spring"),
+		chatMemberUpdate(2, day1, ChatMemberStatusLeft, ChatMemberStatusMember, "https://t.me/+a", "spring"),
+		chatMemberUpdate(3, day1, ChatMemberStatusLeft, ChatMemberStatusMember, "https://t.me/+b", ""),
+		chatMemberUpdate(4, day1, ChatMemberStatusLeft, ChatMemberStatusMember, "", ""),
+		// A promotion is neither a join nor a leave.
+		chatMemberUpdate(1, day2, ChatMemberStatusMember, ChatMemberStatusAdministrator, "", ""),
+		chatMemberUpdate(2, day2, ChatMemberStatusMember, ChatMemberStatusKicked, "", ""),
+		{Message: &Message{MessageId: 1}},
+	}
+
+	for _, u := range updates {
+		if err := analytics.HandleUpdate(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := analytics.Report(-100, day1, day2.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &InviteReport{
+		ChatId: -100,
+		Days: []InviteDayStats{
+			{Day: "2024-03-01", Links: []InviteStats{{Name: "spring", Joins: 2}, {Name: "", Joins: 1}, {Name: "https://t.me/+b", Joins: 1}}},
+			{Day: "2024-03-02", Links: []InviteStats{{Name: "spring", Leaves: 1}}},
+		},
+		Totals: []InviteStats{{Name: "spring", Joins: 2, Leaves: 1}, {Name: "", Joins: 1}, {Name: "https://t.me/+b", Joins: 1}},
+	}
+
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("Report() = %+v\nwant %+v", report, want)
+	}
+
+	if report.Totals[0].Net() != 1 {
+		t.Errorf("Net() = %d, want 1", report.Totals[0].Net())
+	}
+}
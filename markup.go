@@ -0,0 +1,180 @@
+package teledau
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+var (
+	markdownV2Escaper     = newBackslashEscaper("_*[]()~`>#+-=|{}.!\\")
+	markdownV2CodeEscaper = newBackslashEscaper("`\\")
+	markdownV2URLEscaper  = newBackslashEscaper(")\\")
+	markdownEscaper       = newBackslashEscaper("_*`[")
+	htmlEscaper           = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+)
+
+func newBackslashEscaper(chars string) *strings.Replacer {
+	var pairs []string
+	for _, c := range chars {
+		pairs = append(pairs, string(c), "\\"+string(c))
+	}
+
+	return strings.NewReplacer(pairs...)
+}
+
+// EscapeMarkdownV2 escapes s so it is shown literally in MarkdownV2 text outside of code entities.
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+// EscapeMarkdownV2Code escapes s for use inside `code` and ```pre``` entities.
+func EscapeMarkdownV2Code(s string) string {
+	return markdownV2CodeEscaper.Replace(s)
+}
+
+// EscapeMarkdownV2URL escapes s for use inside the (...) part of an inline link.
+func EscapeMarkdownV2URL(s string) string {
+	return markdownV2URLEscaper.Replace(s)
+}
+
+// EscapeMarkdown escapes s for the legacy Markdown parse mode.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// EscapeHTML escapes s for the HTML parse mode.
+func EscapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// Escape escapes s for parseMode, one of the TgParseMod* constants.
+// Text for an empty or unknown parse mode is returned unchanged.
+func Escape(parseMode, s string) string {
+	switch parseMode {
+	case TgParseModMarkdownV2:
+		return EscapeMarkdownV2(s)
+	case TgParseModMarkdownV1:
+		return EscapeMarkdown(s)
+	case TgParseModMarkdownHTML:
+		return EscapeHTML(s)
+	}
+
+	return s
+}
+
+// Markup is text that is already formatted for a parse mode and must not be escaped again
+// by Sprintf or templates created with NewTemplate.
+type Markup string
+
+// Sprintf formats like fmt.Sprintf, but escapes every argument for parseMode.
+// The format string itself is used as written, so it carries the formatting:
+//
+//	Sprintf(TgParseModMarkdownV2, "*%s* scored in the %d\\. minute", player, minute)
+func Sprintf(parseMode, format string, args ...interface{}) string {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = escapedArg{parseMode: parseMode, value: arg}
+	}
+
+	return fmt.Sprintf(format, escaped...)
+}
+
+type escapedArg struct {
+	parseMode string
+	value     interface{}
+}
+
+func (a escapedArg) Format(f fmt.State, verb rune) {
+	var format strings.Builder
+	format.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			format.WriteRune(flag)
+		}
+	}
+
+	if width, ok := f.Width(); ok {
+		format.WriteString(strconv.Itoa(width))
+	}
+
+	if precision, ok := f.Precision(); ok {
+		format.WriteByte('.')
+		format.WriteString(strconv.Itoa(precision))
+	}
+
+	format.WriteRune(verb)
+
+	formatted := fmt.Sprintf(format.String(), a.value)
+	if _, ok := a.value.(Markup); !ok {
+		formatted = Escape(a.parseMode, formatted)
+	}
+
+	fmt.Fprint(f, formatted)
+}
+
+// NewTemplate parses text as a text/template whose actions are escaped for parseMode,
+// while the template text itself is kept as written. Use the "raw" function or pass a Markup
+// value to output already formatted text.
+func NewTemplate(name, parseMode, text string) (*template.Template, error) {
+	escape := func(value interface{}) string {
+		if markup, ok := value.(Markup); ok {
+			return string(markup)
+		}
+
+		return Escape(parseMode, fmt.Sprint(value))
+	}
+
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		templateEscapeFunc: escape,
+		"raw": func(value interface{}) Markup {
+			return Markup(fmt.Sprint(value))
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			escapeTemplateNode(t.Tree, t.Tree.Root)
+		}
+	}
+
+	return tmpl, nil
+}
+
+const templateEscapeFunc = "_teledauEscape"
+
+// escapeTemplateNode appends the escape function to every pipeline that produces output.
+func escapeTemplateNode(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			escapeTemplateNode(tree, child)
+		}
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+
+		escapeCmd := &parse.CommandNode{NodeType: parse.NodeCommand, Pos: n.Pos}
+		escapeCmd.Args = []parse.Node{parse.NewIdentifier(templateEscapeFunc).SetTree(tree).SetPos(n.Pos)}
+		n.Pipe.Cmds = append(n.Pipe.Cmds, escapeCmd)
+	case *parse.IfNode:
+		escapeTemplateNode(tree, n.List)
+		escapeTemplateNode(tree, n.ElseList)
+	case *parse.RangeNode:
+		escapeTemplateNode(tree, n.List)
+		escapeTemplateNode(tree, n.ElseList)
+	case *parse.WithNode:
+		escapeTemplateNode(tree, n.List)
+		escapeTemplateNode(tree, n.ElseList)
+	}
+}
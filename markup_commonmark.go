@@ -0,0 +1,490 @@
+package teledau
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseCommonMark converts CommonMark into plain text and entities. It understands emphasis,
+// strong emphasis, GitHub strikethrough, code spans, fenced code blocks, links, block quotes
+// and headings (rendered bold). Anything else is kept as text. Unlike the Telegram parse modes,
+// CommonMark never fails: unmatched markers are kept literally.
+func ParseCommonMark(text string) (string, []MessageEntity) {
+	var (
+		out      plainText
+		entities []MessageEntity
+	)
+
+	parseCommonMarkBlocks(text, &out, &entities)
+	sortEntities(entities)
+
+	return out.b.String(), entities
+}
+
+func parseCommonMarkBlocks(text string, out *plainText, entities *[]MessageEntity) {
+	lines := strings.Split(text, "\n")
+	first := true
+
+	newline := func() {
+		if !first {
+			out.writeString("\n")
+		}
+		first = false
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if fence, info, ok := commonMarkFence(line); ok {
+			var content []string
+			j := i + 1
+			for ; j < len(lines); j++ {
+				if closing, rest, ok := commonMarkFence(lines[j]); ok && closing[0] == fence[0] && len(closing) >= len(fence) && rest == "" {
+					break
+				}
+
+				content = append(content, lines[j])
+			}
+
+			language := ""
+			if fields := strings.Fields(info); len(fields) > 0 {
+				language = fields[0]
+			}
+
+			newline()
+			start := out.n
+			out.writeString(strings.Join(content, "\n"))
+			*entities = appendEntity(*entities, MessageEntity{Type: EntityTypePre, Offset: start, Language: language}, out.n)
+
+			i = j + 1
+
+			continue
+		}
+
+		if _, ok := commonMarkQuoteLine(line); ok {
+			var quoted []string
+			for ; i < len(lines); i++ {
+				content, ok := commonMarkQuoteLine(lines[i])
+				if !ok {
+					break
+				}
+
+				quoted = append(quoted, content)
+			}
+
+			newline()
+			start := out.n
+			parseCommonMarkBlocks(strings.Join(quoted, "\n"), out, entities)
+			*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeBlockquote, Offset: start}, out.n)
+
+			continue
+		}
+
+		if heading, ok := commonMarkHeading(line); ok {
+			newline()
+			start := out.n
+			parseCommonMarkInline(heading, out, entities)
+			*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeBold, Offset: start}, out.n)
+			i++
+
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			newline()
+			i++
+
+			continue
+		}
+
+		paragraph := []string{line}
+		for i++; i < len(lines); i++ {
+			next := lines[i]
+			if strings.TrimSpace(next) == "" {
+				break
+			}
+
+			if _, _, ok := commonMarkFence(next); ok {
+				break
+			}
+
+			if _, ok := commonMarkQuoteLine(next); ok {
+				break
+			}
+
+			if _, ok := commonMarkHeading(next); ok {
+				break
+			}
+
+			paragraph = append(paragraph, next)
+		}
+
+		newline()
+		parseCommonMarkInline(strings.Join(paragraph, "\n"), out, entities)
+	}
+}
+
+// commonMarkFence reports whether line opens or closes a fenced code block
+// and returns the fence and the info string after it.
+func commonMarkFence(line string) (string, string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return "", "", false
+	}
+
+	n := 0
+	for n < len(trimmed) && trimmed[n] == trimmed[0] {
+		n++
+	}
+
+	if n < 3 {
+		return "", "", false
+	}
+
+	info := strings.TrimSpace(trimmed[n:])
+	if trimmed[0] == '`' && strings.Contains(info, "`") {
+		return "", "", false
+	}
+
+	return trimmed[:n], info, true
+}
+
+func commonMarkQuoteLine(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || !strings.HasPrefix(trimmed, ">") {
+		return "", false
+	}
+
+	content := trimmed[1:]
+	if strings.HasPrefix(content, " ") {
+		content = content[1:]
+	}
+
+	return content, true
+}
+
+func commonMarkHeading(line string) (string, bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return "", false
+	}
+
+	n := 0
+	for n < len(trimmed) && trimmed[n] == '#' {
+		n++
+	}
+
+	if n == 0 || n > 6 || (n < len(trimmed) && trimmed[n] != ' ' && trimmed[n] != '\t') {
+		return "", false
+	}
+
+	heading := strings.TrimSpace(trimmed[n:])
+	if closing := strings.TrimRight(heading, "#"); closing == "" || strings.HasSuffix(closing, " ") {
+		heading = strings.TrimSpace(closing)
+	}
+
+	return heading, true
+}
+
+func parseCommonMarkInline(s string, out *plainText, entities *[]MessageEntity) {
+	for i := 0; i < len(s); {
+		c := s[i]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && isASCIIPunctuation(s[i+1]):
+			out.b.WriteByte(s[i+1])
+			out.n++
+			i += 2
+
+			continue
+		case c == '`':
+			run := countRun(s, i, '`')
+			if end := findCodeSpanEnd(s, i+run, run); end >= 0 {
+				content := strings.ReplaceAll(s[i+run:end], "\n", " ")
+				if len(content) > 1 && content[0] == ' ' && content[len(content)-1] == ' ' && strings.TrimSpace(content) != "" {
+					content = content[1 : len(content)-1]
+				}
+
+				start := out.n
+				out.writeString(content)
+				*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeCode, Offset: start}, out.n)
+				i = end + run
+
+				continue
+			}
+
+			out.writeString(s[i : i+run])
+			i += run
+
+			continue
+		case c == '*' || c == '_' || c == '~':
+			run := countRun(s, i, c)
+			if n := run; (c != '~' || n == 2) && n <= 3 && canOpenEmphasis(s, i, run, c) {
+				if end := findEmphasisEnd(s, i+run, c, n); end >= 0 {
+					start := out.n
+					parseCommonMarkInline(s[i+n:end], out, entities)
+
+					switch {
+					case c == '~':
+						*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeStrikethrough, Offset: start}, out.n)
+					case n == 1:
+						*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeItalic, Offset: start}, out.n)
+					case n == 2:
+						*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeBold, Offset: start}, out.n)
+					default:
+						*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeBold, Offset: start}, out.n)
+						*entities = appendEntity(*entities, MessageEntity{Type: EntityTypeItalic, Offset: start}, out.n)
+					}
+
+					i = end + n
+
+					continue
+				}
+			}
+
+			out.writeString(s[i : i+run])
+			i += run
+
+			continue
+		case c == '[':
+			if closing := findLinkTextEnd(s, i); closing >= 0 && closing+1 < len(s) && s[closing+1] == '(' {
+				if end, url, ok := parseLinkDestination(s, closing+2); ok {
+					start := out.n
+					parseCommonMarkInline(s[i+1:closing], out, entities)
+
+					entity := MessageEntity{Type: EntityTypeTextLink, Offset: start, URL: url}
+					if userId, ok := userIdFromURL(url); ok {
+						entity = MessageEntity{Type: EntityTypeTextMention, Offset: start, User: &From{Id: userId}}
+					}
+
+					*entities = appendEntity(*entities, entity, out.n)
+					i = end
+
+					continue
+				}
+			}
+		case c == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				if target := s[i+1 : i+end]; strings.Contains(target, "://") && !strings.ContainsAny(target, " <") {
+					out.writeString(target)
+					i += end + 1
+
+					continue
+				}
+			}
+		}
+
+		i += out.writeRuneAt(s, i)
+	}
+}
+
+func countRun(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+
+	return n
+}
+
+func isASCIIPunctuation(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func runeBefore(s string, i int) rune {
+	if i <= 0 {
+		return ' '
+	}
+
+	r, _ := utf8.DecodeLastRuneInString(s[:i])
+
+	return r
+}
+
+func runeAt(s string, i int) rune {
+	if i >= len(s) {
+		return ' '
+	}
+
+	r, _ := utf8.DecodeRuneInString(s[i:])
+
+	return r
+}
+
+func canOpenEmphasis(s string, i, run int, c byte) bool {
+	next := runeAt(s, i+run)
+	if unicode.IsSpace(next) {
+		return false
+	}
+
+	prev := runeBefore(s, i)
+
+	return c != '_' || !(unicode.IsLetter(prev) || unicode.IsDigit(prev))
+}
+
+// findEmphasisEnd returns the index of the closing run of n markers c, skipping code spans,
+// escapes and nested emphasis, or -1 if the emphasis is not closed.
+func findEmphasisEnd(s string, i int, c byte, n int) int {
+	var nested []int
+
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			i += 2
+
+			continue
+		case '`':
+			run := countRun(s, i, '`')
+			if end := findCodeSpanEnd(s, i+run, run); end >= 0 {
+				i = end + run
+			} else {
+				i += run
+			}
+
+			continue
+		case c:
+		default:
+			i++
+
+			continue
+		}
+
+		run := countRun(s, i, c)
+		m := run
+		rightFlanking := !unicode.IsSpace(runeBefore(s, i))
+		leftFlanking := !unicode.IsSpace(runeAt(s, i+run))
+
+		if c == '_' {
+			next := runeAt(s, i+run)
+			if unicode.IsLetter(next) || unicode.IsDigit(next) {
+				rightFlanking = false
+			}
+		}
+
+		if rightFlanking && len(nested) > 0 && m >= nested[len(nested)-1] {
+			m -= nested[len(nested)-1]
+			nested = nested[:len(nested)-1]
+		}
+
+		if m > 0 && rightFlanking && m >= n {
+			return i + run - n
+		}
+
+		if m > 0 && leftFlanking {
+			nested = append(nested, m)
+		}
+
+		i += run
+	}
+
+	return -1
+}
+
+func findCodeSpanEnd(s string, i, run int) int {
+	for i < len(s) {
+		j := strings.IndexByte(s[i:], '`')
+		if j < 0 {
+			return -1
+		}
+
+		i += j
+		closing := countRun(s, i, '`')
+		if closing == run {
+			return i
+		}
+
+		i += closing
+	}
+
+	return -1
+}
+
+func findLinkTextEnd(s string, i int) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			run := countRun(s, j, '`')
+			if end := findCodeSpanEnd(s, j+run, run); end >= 0 {
+				j = end + run - 1
+			} else {
+				j += run - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+
+	return -1
+}
+
+// parseLinkDestination reads "url" or "url \"title\"" followed by ")" starting at s[i]
+// and returns the index after ")".
+func parseLinkDestination(s string, i int) (int, string, bool) {
+	for i < len(s) && s[i] == ' ' {
+		i++
+	}
+
+	var url strings.Builder
+	if i < len(s) && s[i] == '<' {
+		end := strings.IndexByte(s[i:], '>')
+		if end < 0 {
+			return 0, "", false
+		}
+
+		url.WriteString(s[i+1 : i+end])
+		i += end + 1
+	} else {
+		depth := 0
+		for i < len(s) && s[i] != ' ' && s[i] != '\n' {
+			if s[i] == '\\' && i+1 < len(s) && isASCIIPunctuation(s[i+1]) {
+				url.WriteByte(s[i+1])
+				i += 2
+
+				continue
+			}
+
+			if s[i] == '(' {
+				depth++
+			} else if s[i] == ')' {
+				if depth == 0 {
+					break
+				}
+				depth--
+			}
+
+			url.WriteByte(s[i])
+			i++
+		}
+	}
+
+	for i < len(s) && (s[i] == ' ' || s[i] == '\n') {
+		i++
+	}
+
+	if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+		end := strings.IndexByte(s[i+1:], s[i])
+		if end < 0 {
+			return 0, "", false
+		}
+
+		i += end + 2
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+	}
+
+	if i >= len(s) || s[i] != ')' {
+		return 0, "", false
+	}
+
+	return i + 1, url.String(), true
+}
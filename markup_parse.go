@@ -0,0 +1,870 @@
+package teledau
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseError is returned when text is not valid for its parse mode, i.e. when Telegram
+// would answer with "can't parse entities". Offset is a byte offset into the source text.
+type ParseError struct {
+	Offset  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return "can't parse entities: " + e.Message
+}
+
+func newParseError(offset int, format string, args ...interface{}) *ParseError {
+	return &ParseError{Offset: offset, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validate reports whether text would be accepted by Telegram for parseMode.
+// An empty parse mode accepts any text.
+func Validate(parseMode, text string) error {
+	_, _, err := Parse(parseMode, text)
+
+	return err
+}
+
+func ValidateMarkdownV2(text string) error {
+	_, _, err := ParseMarkdownV2(text)
+
+	return err
+}
+
+func ValidateHTML(text string) error {
+	_, _, err := ParseHTML(text)
+
+	return err
+}
+
+// Parse converts text formatted for parseMode into plain text and entities,
+// the same way Telegram does when it receives a message with parse_mode.
+func Parse(parseMode, text string) (string, []MessageEntity, error) {
+	switch parseMode {
+	case TgParseModMarkdownV2:
+		return ParseMarkdownV2(text)
+	case TgParseModMarkdownV1:
+		return ParseMarkdown(text)
+	case TgParseModMarkdownHTML:
+		return ParseHTML(text)
+	}
+
+	return text, nil, nil
+}
+
+// plainText accumulates parsed text and tracks its length in UTF-16 code units.
+type plainText struct {
+	b strings.Builder
+	n int
+}
+
+func (p *plainText) writeString(s string) {
+	p.b.WriteString(s)
+	p.n += UTF16Len(s)
+}
+
+// writeRuneAt copies the rune starting at text[i] and returns its size in bytes.
+func (p *plainText) writeRuneAt(text string, i int) int {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	p.b.WriteString(text[i : i+size])
+	p.n += utf16RuneLen(r)
+
+	return size
+}
+
+type openEntity struct {
+	entity     MessageEntity
+	name       string
+	byteOffset int
+}
+
+// ParseMarkdownV2 parses text formatted with the MarkdownV2 parse mode.
+// Carriage returns outside of code are dropped; they separate ambiguous "_" and "__" markers.
+func ParseMarkdownV2(text string) (string, []MessageEntity, error) {
+	var (
+		out      plainText
+		entities []MessageEntity
+		stack    []openEntity
+
+		quoteStart      = -1
+		quoteExpandable bool
+		lineStart       = true
+		prevLineEnd     int
+	)
+
+	closeQuote := func(end int) {
+		if quoteStart >= 0 && end > quoteStart {
+			entityType := EntityTypeBlockquote
+			if quoteExpandable {
+				entityType = EntityTypeExpandableBlockquote
+			}
+
+			entities = append(entities, MessageEntity{Type: entityType, Offset: quoteStart, Length: end - quoteStart})
+		}
+
+		quoteStart = -1
+		quoteExpandable = false
+	}
+
+	inCode := func() bool {
+		if len(stack) == 0 {
+			return false
+		}
+
+		t := stack[len(stack)-1].entity.Type
+
+		return t == EntityTypeCode || t == EntityTypePre
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		if lineStart && !inCode() {
+			lineStart = false
+
+			switch {
+			case strings.HasPrefix(text[i:], "**>"):
+				if quoteStart < 0 || !quoteExpandable {
+					closeQuote(prevLineEnd)
+					quoteStart = out.n
+					quoteExpandable = true
+				}
+				i += 3
+
+				continue
+			case c == '>':
+				if quoteStart < 0 {
+					quoteStart = out.n
+				}
+				i++
+
+				continue
+			default:
+				closeQuote(prevLineEnd)
+			}
+		}
+		lineStart = false
+
+		if c == '\\' && i+1 < len(text) && text[i+1] > 0 && text[i+1] <= 126 {
+			out.b.WriteByte(text[i+1])
+			out.n++
+			i += 2
+
+			continue
+		}
+
+		if inCode() {
+			top := stack[len(stack)-1]
+			isEnd := c == '`' && (top.entity.Type == EntityTypeCode || strings.HasPrefix(text[i:], "```"))
+			if !isEnd {
+				if c == '\n' {
+					prevLineEnd = out.n
+				}
+				i += out.writeRuneAt(text, i)
+
+				continue
+			}
+
+			if top.entity.Type == EntityTypePre {
+				i += 3
+			} else {
+				i++
+			}
+
+			stack = stack[:len(stack)-1]
+			entities = appendEntity(entities, top.entity, out.n)
+
+			continue
+		}
+
+		if c == '\r' {
+			i++
+
+			continue
+		}
+
+		if quoteExpandable && quoteStart >= 0 && strings.HasPrefix(text[i:], "||") &&
+			(i+2 == len(text) || text[i+2] == '\n') &&
+			(len(stack) == 0 || stack[len(stack)-1].entity.Type != EntityTypeSpoiler) {
+			i += 2
+			closeQuote(out.n)
+
+			continue
+		}
+
+		if len(stack) > 0 && isMarkdownV2EntityEnd(stack[len(stack)-1].entity.Type, text, i) {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			entity := top.entity
+
+			switch entity.Type {
+			case EntityTypeUnderline, EntityTypeSpoiler:
+				i += 2
+			case EntityTypeTextLink, EntityTypeCustomEmoji:
+				i++
+
+				url := ""
+				if i < len(text) && text[i] == '(' {
+					end, parsed, err := parseMarkdownV2URL(text, i+1)
+					if err != nil {
+						return "", nil, err
+					}
+
+					url = parsed
+					i = end
+				} else if entity.Type == EntityTypeTextLink {
+					url = out.b.String()[top.byteOffset:]
+				}
+
+				if entity.Type == EntityTypeCustomEmoji {
+					id, ok := customEmojiIdFromURL(url)
+					if !ok {
+						return "", nil, newParseError(top.byteOffset, "Custom emoji entity must contain a tg://emoji URL")
+					}
+
+					entity.CustomEmojiId = id
+				} else if userId, ok := userIdFromURL(url); ok {
+					entity.Type = EntityTypeTextMention
+					entity.User = &From{Id: userId}
+				} else {
+					entity.URL = url
+				}
+			default:
+				i++
+			}
+
+			entities = appendEntity(entities, entity, out.n)
+
+			continue
+		}
+
+		if !strings.ContainsRune("_*[]()~`>#+-=|{}.!", rune(c)) {
+			if c == '\n' {
+				prevLineEnd = out.n
+				lineStart = true
+			}
+			i += out.writeRuneAt(text, i)
+
+			continue
+		}
+
+		var (
+			entity MessageEntity
+			name   string
+		)
+
+		switch c {
+		case '_':
+			if i+1 < len(text) && text[i+1] == '_' {
+				entity.Type, name = EntityTypeUnderline, "Underline"
+				i++
+			} else {
+				entity.Type, name = EntityTypeItalic, "Italic"
+			}
+		case '*':
+			entity.Type, name = EntityTypeBold, "Bold"
+		case '~':
+			entity.Type, name = EntityTypeStrikethrough, "Strikethrough"
+		case '|':
+			if i+1 >= len(text) || text[i+1] != '|' {
+				return "", nil, reservedCharacterError(i, c)
+			}
+
+			entity.Type, name = EntityTypeSpoiler, "Spoiler"
+			i++
+		case '[':
+			entity.Type, name = EntityTypeTextLink, "TextUrl"
+		case '!':
+			if i+1 >= len(text) || text[i+1] != '[' {
+				return "", nil, reservedCharacterError(i, c)
+			}
+
+			entity.Type, name = EntityTypeCustomEmoji, "CustomEmoji"
+			i++
+		case '`':
+			if strings.HasPrefix(text[i:], "```") {
+				entity.Type, name = EntityTypePre, "Pre"
+				i += 3
+
+				languageEnd := i
+				for languageEnd < len(text) && !isMarkupSpace(text[languageEnd]) && text[languageEnd] != '`' {
+					languageEnd++
+				}
+
+				if languageEnd != i && languageEnd < len(text) && text[languageEnd] != '`' {
+					entity.Language = text[i:languageEnd]
+					i = languageEnd
+				}
+
+				if i < len(text) && (text[i] == '\n' || text[i] == '\r') {
+					if i+1 < len(text) && (text[i+1] == '\n' || text[i+1] == '\r') && text[i] != text[i+1] {
+						i += 2
+					} else {
+						i++
+					}
+				}
+
+				i--
+			} else {
+				entity.Type, name = EntityTypeCode, "Code"
+			}
+		default:
+			return "", nil, reservedCharacterError(i, c)
+		}
+
+		i++
+		entity.Offset = out.n
+		stack = append(stack, openEntity{entity: entity, name: name, byteOffset: out.b.Len()})
+	}
+
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+
+		return "", nil, newParseError(top.byteOffset, "Can't find end of %s entity at byte offset %d", top.name, top.byteOffset)
+	}
+
+	if lineStart {
+		closeQuote(prevLineEnd)
+	} else {
+		closeQuote(out.n)
+	}
+
+	sortEntities(entities)
+
+	return out.b.String(), entities, nil
+}
+
+func isMarkdownV2EntityEnd(entityType string, text string, i int) bool {
+	c := text[i]
+	next := byte(0)
+	if i+1 < len(text) {
+		next = text[i+1]
+	}
+
+	switch entityType {
+	case EntityTypeBold:
+		return c == '*'
+	case EntityTypeItalic:
+		return c == '_' && next != '_'
+	case EntityTypeUnderline:
+		return c == '_' && next == '_'
+	case EntityTypeStrikethrough:
+		return c == '~'
+	case EntityTypeSpoiler:
+		return c == '|' && next == '|'
+	case EntityTypeTextLink, EntityTypeCustomEmoji:
+		return c == ']'
+	}
+
+	return false
+}
+
+// parseMarkdownV2URL reads a link target starting after "(" and returns the index after ")".
+func parseMarkdownV2URL(text string, i int) (int, string, error) {
+	start := i
+
+	var url strings.Builder
+	for i < len(text) && text[i] != ')' {
+		if text[i] == '\\' && i+1 < len(text) && text[i+1] > 0 && text[i+1] <= 126 {
+			url.WriteByte(text[i+1])
+			i += 2
+
+			continue
+		}
+
+		url.WriteByte(text[i])
+		i++
+	}
+
+	if i == len(text) {
+		return 0, "", newParseError(start, "Can't find end of a URL at byte offset %d", start)
+	}
+
+	return i + 1, url.String(), nil
+}
+
+func reservedCharacterError(offset int, c byte) error {
+	return newParseError(offset, "Character '%c' is reserved and must be escaped with the preceding '\\'", c)
+}
+
+func isMarkupSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\v' || c == '\f'
+}
+
+// appendEntity finishes entity at the given end offset, dropping empty entities like Telegram does.
+func appendEntity(entities []MessageEntity, entity MessageEntity, end int) []MessageEntity {
+	entity.Length = end - entity.Offset
+	if entity.Length <= 0 {
+		return entities
+	}
+
+	return append(entities, entity)
+}
+
+// sortEntities orders entities the way Telegram returns them: by offset, outer entities first.
+func sortEntities(entities []MessageEntity) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Offset != entities[j].Offset {
+			return entities[i].Offset < entities[j].Offset
+		}
+
+		return entities[i].Length > entities[j].Length
+	})
+}
+
+func userIdFromURL(url string) (int64, bool) {
+	const prefix = "tg://user?id="
+	if !strings.HasPrefix(url, prefix) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(url[len(prefix):], 10, 64)
+
+	return id, err == nil && id > 0
+}
+
+func customEmojiIdFromURL(url string) (string, bool) {
+	const prefix = "tg://emoji?id="
+	if !strings.HasPrefix(url, prefix) {
+		return "", false
+	}
+
+	id := url[len(prefix):]
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return "", false
+	}
+
+	return id, true
+}
+
+// ParseMarkdown parses text formatted with the legacy Markdown parse mode,
+// which supports bold, italic, code, pre and links without nesting.
+func ParseMarkdown(text string) (string, []MessageEntity, error) {
+	var (
+		out      plainText
+		entities []MessageEntity
+	)
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		if c == '\\' && i+1 < len(text) && strings.IndexByte("_*`[", text[i+1]) >= 0 {
+			out.b.WriteByte(text[i+1])
+			out.n++
+			i += 2
+
+			continue
+		}
+
+		if strings.IndexByte("_*`[", c) < 0 {
+			i += out.writeRuneAt(text, i)
+
+			continue
+		}
+
+		begin := i
+		endCharacter := c
+		if c == '[' {
+			endCharacter = ']'
+		}
+
+		i++
+
+		isPre := false
+		language := ""
+		if c == '`' && strings.HasPrefix(text[i:], "``") {
+			isPre = true
+			i += 2
+
+			languageEnd := i
+			for languageEnd < len(text) && !isMarkupSpace(text[languageEnd]) && text[languageEnd] != '`' {
+				languageEnd++
+			}
+
+			if languageEnd != i && languageEnd < len(text) && text[languageEnd] != '`' {
+				language = text[i:languageEnd]
+				i = languageEnd
+			}
+
+			if i < len(text) && text[i] == '\n' {
+				i++
+			}
+		}
+
+		offset := out.n
+		for i < len(text) && (text[i] != endCharacter || (isPre && !strings.HasPrefix(text[i:], "```"))) {
+			i += out.writeRuneAt(text, i)
+		}
+
+		if i == len(text) {
+			return "", nil, newParseError(begin, "Can't find end of the entity starting at byte offset %d", begin)
+		}
+
+		entity := MessageEntity{Offset: offset}
+		switch c {
+		case '_':
+			entity.Type = EntityTypeItalic
+		case '*':
+			entity.Type = EntityTypeBold
+		case '`':
+			if isPre {
+				entity.Type = EntityTypePre
+				entity.Language = language
+			} else {
+				entity.Type = EntityTypeCode
+			}
+		case '[':
+			url := ""
+			if i+1 < len(text) && text[i+1] == '(' {
+				end := strings.IndexByte(text[i+2:], ')')
+				if end >= 0 {
+					url = text[i+2 : i+2+end]
+					i = i + 2 + end
+				}
+			}
+
+			if userId, ok := userIdFromURL(url); ok {
+				entity.Type = EntityTypeTextMention
+				entity.User = &From{Id: userId}
+			} else if url != "" {
+				entity.Type = EntityTypeTextLink
+				entity.URL = url
+			}
+		}
+
+		if entity.Type != "" {
+			entities = appendEntity(entities, entity, out.n)
+		}
+
+		if isPre {
+			i += 2
+		}
+		i++
+	}
+
+	sortEntities(entities)
+
+	return out.b.String(), entities, nil
+}
+
+var htmlTagEntityTypes = map[string]string{
+	"a":          EntityTypeTextLink,
+	"b":          EntityTypeBold,
+	"strong":     EntityTypeBold,
+	"i":          EntityTypeItalic,
+	"em":         EntityTypeItalic,
+	"u":          EntityTypeUnderline,
+	"ins":        EntityTypeUnderline,
+	"s":          EntityTypeStrikethrough,
+	"strike":     EntityTypeStrikethrough,
+	"del":        EntityTypeStrikethrough,
+	"span":       EntityTypeSpoiler,
+	"tg-spoiler": EntityTypeSpoiler,
+	"tg-emoji":   EntityTypeCustomEmoji,
+	"code":       EntityTypeCode,
+	"pre":        EntityTypePre,
+	"blockquote": EntityTypeBlockquote,
+}
+
+type openTag struct {
+	name       string
+	entity     MessageEntity
+	byteOffset int
+}
+
+// ParseHTML parses text formatted with the HTML parse mode.
+func ParseHTML(text string) (string, []MessageEntity, error) {
+	var (
+		out      plainText
+		entities []MessageEntity
+		stack    []openTag
+	)
+
+	for i := 0; i < len(text); {
+		c := text[i]
+
+		if c == '&' {
+			if decoded, size, ok := decodeHTMLEntity(text[i:]); ok {
+				out.writeString(decoded)
+				i += size
+
+				continue
+			}
+
+			out.b.WriteByte(c)
+			out.n++
+			i++
+
+			continue
+		}
+
+		if c != '<' {
+			i += out.writeRuneAt(text, i)
+
+			continue
+		}
+
+		begin := i
+		i++
+
+		if i < len(text) && text[i] == '/' {
+			i++
+			nameEnd := i
+			for nameEnd < len(text) && text[nameEnd] != '>' && !isMarkupSpace(text[nameEnd]) {
+				nameEnd++
+			}
+
+			name := strings.ToLower(text[i:nameEnd])
+			for nameEnd < len(text) && isMarkupSpace(text[nameEnd]) {
+				nameEnd++
+			}
+
+			if nameEnd >= len(text) || text[nameEnd] != '>' {
+				return "", nil, newParseError(begin, "Can't find end tag at byte offset %d", begin)
+			}
+
+			if len(stack) == 0 {
+				return "", nil, newParseError(begin, "Unexpected end tag at byte offset %d", begin)
+			}
+
+			top := stack[len(stack)-1]
+			if top.name != name {
+				return "", nil, newParseError(begin, "Unmatched end tag at byte offset %d, expected \"</%s>\", found \"</%s>\"", begin, top.name, name)
+			}
+
+			stack = stack[:len(stack)-1]
+			i = nameEnd + 1
+
+			entity := top.entity
+			if top.name == "a" && entity.URL == "" && entity.User == nil {
+				entity.URL = out.b.String()[top.byteOffset:]
+			}
+
+			if top.name == "code" && len(stack) > 0 && stack[len(stack)-1].name == "pre" &&
+				stack[len(stack)-1].entity.Offset == entity.Offset && entity.Language != "" {
+				stack[len(stack)-1].entity.Language = entity.Language
+
+				continue
+			}
+
+			entity.Language = strings.TrimSpace(entity.Language)
+			if entity.Type == EntityTypeCode {
+				entity.Language = ""
+			}
+
+			entities = appendEntity(entities, entity, out.n)
+
+			continue
+		}
+
+		nameEnd := i
+		for nameEnd < len(text) && !isMarkupSpace(text[nameEnd]) && text[nameEnd] != '>' && text[nameEnd] != '/' {
+			nameEnd++
+		}
+
+		if nameEnd == len(text) {
+			return "", nil, newParseError(begin, "Unclosed start tag at byte offset %d", begin)
+		}
+
+		name := strings.ToLower(text[i:nameEnd])
+		entityType, ok := htmlTagEntityTypes[name]
+		if !ok {
+			return "", nil, newParseError(begin, "Unsupported start tag \"%s\" at byte offset %d", name, begin)
+		}
+
+		attributes, end, err := parseHTMLAttributes(text, nameEnd, begin)
+		if err != nil {
+			return "", nil, err
+		}
+		i = end
+
+		entity := MessageEntity{Type: entityType, Offset: out.n}
+		switch name {
+		case "a":
+			href := attributes["href"]
+			if userId, ok := userIdFromURL(href); ok {
+				entity.Type = EntityTypeTextMention
+				entity.User = &From{Id: userId}
+			} else {
+				entity.URL = href
+			}
+		case "span":
+			if attributes["class"] != "tg-spoiler" {
+				return "", nil, newParseError(begin, "Tag \"span\" must have class \"tg-spoiler\" at byte offset %d", begin)
+			}
+		case "tg-emoji":
+			id := attributes["emoji-id"]
+			if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+				return "", nil, newParseError(begin, "Invalid custom emoji identifier specified at byte offset %d", begin)
+			}
+
+			entity.CustomEmojiId = id
+		case "code":
+			if class := attributes["class"]; strings.HasPrefix(class, "language-") {
+				entity.Language = class[len("language-"):]
+			}
+		case "blockquote":
+			if _, ok := attributes["expandable"]; ok {
+				entity.Type = EntityTypeExpandableBlockquote
+			}
+		}
+
+		stack = append(stack, openTag{name: name, entity: entity, byteOffset: out.b.Len()})
+	}
+
+	if len(stack) > 0 {
+		top := stack[len(stack)-1]
+
+		return "", nil, newParseError(len(text), "Can't find end tag corresponding to start tag \"%s\"", top.name)
+	}
+
+	sortEntities(entities)
+
+	return out.b.String(), entities, nil
+}
+
+// parseHTMLAttributes reads attributes from text[i:] up to the closing ">" of a start tag
+// and returns them with the index after ">".
+func parseHTMLAttributes(text string, i, begin int) (map[string]string, int, error) {
+	attributes := make(map[string]string)
+
+	for {
+		for i < len(text) && isMarkupSpace(text[i]) {
+			i++
+		}
+
+		if i >= len(text) {
+			return nil, 0, newParseError(begin, "Unclosed start tag at byte offset %d", begin)
+		}
+
+		if text[i] == '>' {
+			return attributes, i + 1, nil
+		}
+
+		if text[i] == '/' && i+1 < len(text) && text[i+1] == '>' {
+			return nil, 0, newParseError(begin, "Self-closing tags are not supported at byte offset %d", begin)
+		}
+
+		nameEnd := i
+		for nameEnd < len(text) && !isMarkupSpace(text[nameEnd]) && text[nameEnd] != '=' && text[nameEnd] != '>' {
+			nameEnd++
+		}
+
+		if nameEnd == i {
+			return nil, 0, newParseError(begin, "Empty attribute name in the tag at byte offset %d", begin)
+		}
+
+		name := strings.ToLower(text[i:nameEnd])
+		i = nameEnd
+
+		for i < len(text) && isMarkupSpace(text[i]) {
+			i++
+		}
+
+		if i >= len(text) || text[i] != '=' {
+			attributes[name] = ""
+
+			continue
+		}
+
+		i++
+		for i < len(text) && isMarkupSpace(text[i]) {
+			i++
+		}
+
+		if i >= len(text) {
+			return nil, 0, newParseError(begin, "Unclosed start tag at byte offset %d", begin)
+		}
+
+		var value string
+		if quote := text[i]; quote == '"' || quote == '\'' {
+			end := strings.IndexByte(text[i+1:], quote)
+			if end < 0 {
+				return nil, 0, newParseError(begin, "Unclosed start tag at byte offset %d", begin)
+			}
+
+			value = text[i+1 : i+1+end]
+			i = i + 2 + end
+		} else {
+			valueEnd := i
+			for valueEnd < len(text) && !isMarkupSpace(text[valueEnd]) && text[valueEnd] != '>' {
+				valueEnd++
+			}
+
+			value = text[i:valueEnd]
+			i = valueEnd
+		}
+
+		attributes[name] = decodeHTMLEntities(value)
+	}
+}
+
+func decodeHTMLEntities(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] == '&' {
+			if decoded, size, ok := decodeHTMLEntity(s[i:]); ok {
+				b.WriteString(decoded)
+				i += size
+
+				continue
+			}
+		}
+
+		b.WriteByte(s[i])
+		i++
+	}
+
+	return b.String()
+}
+
+// decodeHTMLEntity decodes an HTML entity at the start of s. Only &lt;, &gt;, &amp;, &quot;
+// and numeric entities are supported, like in Telegram.
+func decodeHTMLEntity(s string) (string, int, bool) {
+	end := strings.IndexByte(s, ';')
+	if end < 2 || end > 10 {
+		return "", 0, false
+	}
+
+	name := s[1:end]
+	switch name {
+	case "lt":
+		return "<", end + 1, true
+	case "gt":
+		return ">", end + 1, true
+	case "amp":
+		return "&", end + 1, true
+	case "quot":
+		return "\"", end + 1, true
+	}
+
+	if name[0] != '#' || len(name) < 2 {
+		return "", 0, false
+	}
+
+	var (
+		code int64
+		err  error
+	)
+	if name[1] == 'x' || name[1] == 'X' {
+		code, err = strconv.ParseInt(name[2:], 16, 32)
+	} else {
+		code, err = strconv.ParseInt(name[1:], 10, 32)
+	}
+
+	if err != nil || code <= 0 || code > utf8.MaxRune || !utf8.ValidRune(rune(code)) {
+		return "", 0, false
+	}
+
+	return string(rune(code)), end + 1, true
+}
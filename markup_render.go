@@ -0,0 +1,602 @@
+package teledau
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+)
+
+// Render formats text and entities for parseMode, one of the TgParseMod* constants,
+// so that sending the result with that parse mode reproduces the original formatting.
+// The legacy Markdown mode has no nesting and only keeps bold, italic, code, pre and links.
+func Render(parseMode, text string, entities []MessageEntity) string {
+	switch parseMode {
+	case TgParseModMarkdownV2:
+		return RenderMarkdownV2(text, entities)
+	case TgParseModMarkdownV1:
+		return renderEntities(text, entities, markdownRenderer{})
+	case TgParseModMarkdownHTML:
+		return RenderHTML(text, entities)
+	}
+
+	return text
+}
+
+// RenderMarkdownV2 formats text and entities as MarkdownV2.
+func RenderMarkdownV2(text string, entities []MessageEntity) string {
+	return renderEntities(text, entities, markdownV2Renderer{})
+}
+
+// RenderHTML formats text and entities for the HTML parse mode.
+func RenderHTML(text string, entities []MessageEntity) string {
+	return renderEntities(text, entities, htmlRenderer{})
+}
+
+// RenderCommonMark formats text and entities as CommonMark with the GitHub strikethrough extension.
+// Underline, spoiler and custom emoji have no CommonMark equivalent and are rendered as plain text.
+func RenderCommonMark(text string, entities []MessageEntity) string {
+	return renderEntities(text, trimEmphasisSpaces(text, entities), commonMarkRenderer{})
+}
+
+// FormattedText returns the message text, or the caption for media messages,
+// rendered for parseMode together with its entities.
+func (m Message) FormattedText(parseMode string) string {
+	if m.Text != "" {
+		return Render(parseMode, m.Text, m.Entities)
+	}
+
+	return Render(parseMode, m.Caption, m.CaptionEntities)
+}
+
+type entityRenderer interface {
+	// open returns the markup starting entity; content is the text the entity covers.
+	open(entity MessageEntity, content string) string
+	close(entity MessageEntity, content string) string
+	escape(s string, inCode bool) string
+	// quoteLine returns the prefix written after a line break inside a blockquote.
+	quoteLine(entity MessageEntity) string
+	// nested reports whether entities may be nested inside each other.
+	nested() bool
+}
+
+// markerSeparator is implemented by renderers whose adjacent markers can merge into a different one.
+type markerSeparator interface {
+	separator(last, next byte) string
+}
+
+// newlineCloser is implemented by renderers that must close an entity before the line break
+// it ends with, because their closing marker can't start a line.
+type newlineCloser interface {
+	closeBeforeNewline(entity MessageEntity) bool
+}
+
+type renderedEntity struct {
+	entity  MessageEntity
+	end     int
+	content string
+}
+
+func renderEntities(text string, entities []MessageEntity, r entityRenderer) string {
+	runes := []rune(text)
+	units := utf16.Encode(runes)
+
+	var sorted []renderedEntity
+	for _, entity := range entities {
+		if entity.Offset < 0 || entity.Length <= 0 || entity.Offset >= len(units) {
+			continue
+		}
+
+		end := entity.Offset + entity.Length
+		if end > len(units) {
+			end = len(units)
+		}
+
+		sorted = append(sorted, renderedEntity{
+			entity:  entity,
+			end:     end,
+			content: string(utf16.Decode(units[entity.Offset:end])),
+		})
+	}
+
+	sortRenderedEntities(sorted)
+
+	var (
+		out   strings.Builder
+		stack []renderedEntity
+		next  int
+		pos   int
+	)
+
+	writeMarker := func(marker string) {
+		if separator, ok := r.(markerSeparator); ok && out.Len() > 0 && marker != "" {
+			last := out.String()[out.Len()-1]
+			out.WriteString(separator.separator(last, marker[0]))
+		}
+
+		out.WriteString(marker)
+	}
+
+	inCode := func() bool {
+		for _, open := range stack {
+			if isCodeEntity(open.entity) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	closeAt := func(pos int) {
+		first := -1
+		for i, open := range stack {
+			if open.end <= pos {
+				first = i
+
+				break
+			}
+		}
+
+		if first < 0 {
+			return
+		}
+
+		var reopen []renderedEntity
+		for len(stack) > first {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			writeMarker(r.close(top.entity, top.content))
+
+			if top.end > pos {
+				reopen = append([]renderedEntity{top}, reopen...)
+			}
+		}
+
+		for _, open := range reopen {
+			writeMarker(r.open(open.entity, open.content))
+			stack = append(stack, open)
+		}
+	}
+
+	for _, c := range runes {
+		closeAt(pos)
+
+		for next < len(sorted) && sorted[next].entity.Offset <= pos {
+			entity := sorted[next]
+			next++
+
+			if entity.end <= pos || inCode() || (!r.nested() && len(stack) > 0) || !isRenderedEntity(entity.entity) {
+				continue
+			}
+
+			writeMarker(r.open(entity.entity, entity.content))
+			stack = append(stack, entity)
+		}
+
+		if closer, ok := r.(newlineCloser); ok && c == '\n' {
+			closeEarly := false
+			for i, open := range stack {
+				if open.end == pos+1 && closer.closeBeforeNewline(open.entity) {
+					stack[i].end, closeEarly = pos, true
+				}
+			}
+
+			if closeEarly {
+				closeAt(pos)
+			}
+		}
+
+		out.WriteString(r.escape(string(c), inCode()))
+		pos += utf16RuneLen(c)
+
+		if c == '\n' {
+			for _, open := range stack {
+				if isBlockquote(open.entity) && open.end > pos {
+					out.WriteString(r.quoteLine(open.entity))
+				}
+			}
+		}
+	}
+
+	closeAt(pos)
+
+	return out.String()
+}
+
+func sortRenderedEntities(entities []renderedEntity) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].entity.Offset != entities[j].entity.Offset {
+			return entities[i].entity.Offset < entities[j].entity.Offset
+		}
+
+		if entities[i].end != entities[j].end {
+			return entities[i].end > entities[j].end
+		}
+
+		// Nothing opens inside code, so on the same range code and pre open innermost.
+		return !isCodeEntity(entities[i].entity) && isCodeEntity(entities[j].entity)
+	})
+}
+
+func isCodeEntity(entity MessageEntity) bool {
+	return entity.Type == EntityTypeCode || entity.Type == EntityTypePre
+}
+
+// isRenderedEntity reports whether the entity carries formatting. Mentions, hashtags,
+// URLs and similar entities are detected by Telegram from the text itself.
+func isRenderedEntity(entity MessageEntity) bool {
+	switch entity.Type {
+	case EntityTypeBold, EntityTypeItalic, EntityTypeUnderline, EntityTypeStrikethrough, EntityTypeSpoiler,
+		EntityTypeCode, EntityTypePre, EntityTypeTextLink, EntityTypeTextMention, EntityTypeCustomEmoji,
+		EntityTypeBlockquote, EntityTypeExpandableBlockquote:
+		return true
+	}
+
+	return false
+}
+
+func isBlockquote(entity MessageEntity) bool {
+	return entity.Type == EntityTypeBlockquote || entity.Type == EntityTypeExpandableBlockquote
+}
+
+func mentionURL(entity MessageEntity) string {
+	if entity.User == nil {
+		return ""
+	}
+
+	return "tg://user?id=" + strconv.FormatInt(entity.User.Id, 10)
+}
+
+type markdownV2Renderer struct{}
+
+func (markdownV2Renderer) open(entity MessageEntity, _ string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "*"
+	case EntityTypeItalic:
+		return "_"
+	case EntityTypeUnderline:
+		return "__"
+	case EntityTypeStrikethrough:
+		return "~"
+	case EntityTypeSpoiler:
+		return "||"
+	case EntityTypeCode:
+		return "`"
+	case EntityTypePre:
+		return "```" + entity.Language + "\n"
+	case EntityTypeTextLink, EntityTypeTextMention:
+		return "["
+	case EntityTypeCustomEmoji:
+		return "!["
+	case EntityTypeBlockquote:
+		return ">"
+	case EntityTypeExpandableBlockquote:
+		return "**>"
+	}
+
+	return ""
+}
+
+func (markdownV2Renderer) close(entity MessageEntity, _ string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "*"
+	case EntityTypeItalic:
+		return "_"
+	case EntityTypeUnderline:
+		return "__"
+	case EntityTypeStrikethrough:
+		return "~"
+	case EntityTypeSpoiler:
+		return "||"
+	case EntityTypeCode:
+		return "`"
+	case EntityTypePre:
+		return "```"
+	case EntityTypeTextLink:
+		return "](" + EscapeMarkdownV2URL(entity.URL) + ")"
+	case EntityTypeTextMention:
+		return "](" + mentionURL(entity) + ")"
+	case EntityTypeCustomEmoji:
+		return "](tg://emoji?id=" + entity.CustomEmojiId + ")"
+	case EntityTypeExpandableBlockquote:
+		return "||"
+	}
+
+	return ""
+}
+
+func (markdownV2Renderer) escape(s string, inCode bool) string {
+	if inCode {
+		return EscapeMarkdownV2Code(s)
+	}
+
+	return EscapeMarkdownV2(s)
+}
+
+// closeBeforeNewline ends an expandable blockquote on its last line: a "||" at the start
+// of the next line would open a spoiler instead.
+func (markdownV2Renderer) closeBeforeNewline(entity MessageEntity) bool {
+	return entity.Type == EntityTypeExpandableBlockquote
+}
+
+// separator keeps "_" (italic) and "__" (underline) apart with a "\r", which Telegram ignores.
+func (markdownV2Renderer) separator(last, next byte) string {
+	if last == '_' && next == '_' {
+		return "\r"
+	}
+
+	return ""
+}
+
+func (markdownV2Renderer) quoteLine(MessageEntity) string {
+	return ">"
+}
+
+func (markdownV2Renderer) nested() bool {
+	return true
+}
+
+type markdownRenderer struct{}
+
+func (markdownRenderer) open(entity MessageEntity, _ string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "*"
+	case EntityTypeItalic:
+		return "_"
+	case EntityTypeCode:
+		return "`"
+	case EntityTypePre:
+		return "```" + entity.Language + "\n"
+	case EntityTypeTextLink, EntityTypeTextMention:
+		return "["
+	}
+
+	return ""
+}
+
+func (markdownRenderer) close(entity MessageEntity, _ string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "*"
+	case EntityTypeItalic:
+		return "_"
+	case EntityTypeCode:
+		return "`"
+	case EntityTypePre:
+		return "```"
+	case EntityTypeTextLink:
+		return "](" + entity.URL + ")"
+	case EntityTypeTextMention:
+		return "](" + mentionURL(entity) + ")"
+	}
+
+	return ""
+}
+
+func (markdownRenderer) escape(s string, inCode bool) string {
+	if inCode {
+		return s
+	}
+
+	return EscapeMarkdown(s)
+}
+
+func (markdownRenderer) quoteLine(MessageEntity) string {
+	return ""
+}
+
+func (markdownRenderer) nested() bool {
+	return false
+}
+
+type htmlRenderer struct{}
+
+func (htmlRenderer) open(entity MessageEntity, _ string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "<b>"
+	case EntityTypeItalic:
+		return "<i>"
+	case EntityTypeUnderline:
+		return "<u>"
+	case EntityTypeStrikethrough:
+		return "<s>"
+	case EntityTypeSpoiler:
+		return "<tg-spoiler>"
+	case EntityTypeCode:
+		return "<code>"
+	case EntityTypePre:
+		if entity.Language != "" {
+			return `<pre><code class="language-` + EscapeHTML(entity.Language) + `">`
+		}
+
+		return "<pre>"
+	case EntityTypeTextLink:
+		return `<a href="` + EscapeHTML(entity.URL) + `">`
+	case EntityTypeTextMention:
+		return `<a href="` + mentionURL(entity) + `">`
+	case EntityTypeCustomEmoji:
+		return `<tg-emoji emoji-id="` + EscapeHTML(entity.CustomEmojiId) + `">`
+	case EntityTypeBlockquote:
+		return "<blockquote>"
+	case EntityTypeExpandableBlockquote:
+		return "<blockquote expandable>"
+	}
+
+	return ""
+}
+
+func (htmlRenderer) close(entity MessageEntity, _ string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "</b>"
+	case EntityTypeItalic:
+		return "</i>"
+	case EntityTypeUnderline:
+		return "</u>"
+	case EntityTypeStrikethrough:
+		return "</s>"
+	case EntityTypeSpoiler:
+		return "</tg-spoiler>"
+	case EntityTypeCode:
+		return "</code>"
+	case EntityTypePre:
+		if entity.Language != "" {
+			return "</code></pre>"
+		}
+
+		return "</pre>"
+	case EntityTypeTextLink, EntityTypeTextMention:
+		return "</a>"
+	case EntityTypeCustomEmoji:
+		return "</tg-emoji>"
+	case EntityTypeBlockquote, EntityTypeExpandableBlockquote:
+		return "</blockquote>"
+	}
+
+	return ""
+}
+
+func (htmlRenderer) escape(s string, _ bool) string {
+	return EscapeHTML(s)
+}
+
+func (htmlRenderer) quoteLine(MessageEntity) string {
+	return ""
+}
+
+func (htmlRenderer) nested() bool {
+	return true
+}
+
+var commonMarkEscaper = newBackslashEscaper("\\`*_[]()#+-.!<>|~")
+
+type commonMarkRenderer struct{}
+
+func (commonMarkRenderer) open(entity MessageEntity, content string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "**"
+	case EntityTypeItalic:
+		return "*"
+	case EntityTypeStrikethrough:
+		return "~~"
+	case EntityTypeCode:
+		fence := backtickFence(content, 1)
+		if strings.HasPrefix(content, "`") || strings.HasSuffix(content, "`") {
+			return fence + " "
+		}
+
+		return fence
+	case EntityTypePre:
+		return backtickFence(content, 3) + entity.Language + "\n"
+	case EntityTypeTextLink, EntityTypeTextMention:
+		return "["
+	case EntityTypeBlockquote, EntityTypeExpandableBlockquote:
+		return "> "
+	}
+
+	return ""
+}
+
+func (commonMarkRenderer) close(entity MessageEntity, content string) string {
+	switch entity.Type {
+	case EntityTypeBold:
+		return "**"
+	case EntityTypeItalic:
+		return "*"
+	case EntityTypeStrikethrough:
+		return "~~"
+	case EntityTypeCode:
+		fence := backtickFence(content, 1)
+		if strings.HasPrefix(content, "`") || strings.HasSuffix(content, "`") {
+			return " " + fence
+		}
+
+		return fence
+	case EntityTypePre:
+		if strings.HasSuffix(content, "\n") {
+			return backtickFence(content, 3)
+		}
+
+		return "\n" + backtickFence(content, 3)
+	case EntityTypeTextLink:
+		return "](" + strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(entity.URL) + ")"
+	case EntityTypeTextMention:
+		return "](" + mentionURL(entity) + ")"
+	}
+
+	return ""
+}
+
+func (commonMarkRenderer) escape(s string, inCode bool) string {
+	if inCode {
+		return s
+	}
+
+	return commonMarkEscaper.Replace(s)
+}
+
+func (commonMarkRenderer) quoteLine(MessageEntity) string {
+	return "> "
+}
+
+func (commonMarkRenderer) nested() bool {
+	return true
+}
+
+// backtickFence returns a run of backticks longer than any run inside content and at least min long.
+func backtickFence(content string, min int) string {
+	longest, current := 0, 0
+	for _, c := range content {
+		if c == '`' {
+			current++
+			if current > longest {
+				longest = current
+			}
+		} else {
+			current = 0
+		}
+	}
+
+	if longest+1 > min {
+		min = longest + 1
+	}
+
+	return strings.Repeat("`", min)
+}
+
+// trimEmphasisSpaces shrinks bold, italic and strikethrough entities so they neither start
+// nor end with whitespace, which CommonMark does not accept next to emphasis markers.
+func trimEmphasisSpaces(text string, entities []MessageEntity) []MessageEntity {
+	units := utf16.Encode([]rune(text))
+
+	isSpace := func(i int) bool {
+		return i >= 0 && i < len(units) && unicode.IsSpace(rune(units[i]))
+	}
+
+	trimmed := make([]MessageEntity, 0, len(entities))
+	for _, entity := range entities {
+		switch entity.Type {
+		case EntityTypeBold, EntityTypeItalic, EntityTypeStrikethrough:
+			for entity.Length > 0 && isSpace(entity.Offset) {
+				entity.Offset++
+				entity.Length--
+			}
+
+			for entity.Length > 0 && isSpace(entity.Offset+entity.Length-1) {
+				entity.Length--
+			}
+		}
+
+		if entity.Length > 0 {
+			trimmed = append(trimmed, entity)
+		}
+	}
+
+	return trimmed
+}
@@ -0,0 +1,169 @@
+package teledau
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sampleFormattedText() (string, []MessageEntity) {
+	b := NewTextBuilder()
+	b.Bold("Arsenal 2-1").Text(" (").
+		Nested(MessageEntity{Type: EntityTypeItalic}, func(b *TextBuilder) {
+			b.Text("full ").Underline("time")
+		}).
+		Text(") ").
+		Link("report_1", "https://example.com/a_(b)").
+		Text(" ").
+		Code("x := `y`").
+		Text(" ").
+		Mention("Saka", From{Id: 7}).
+		Text("\n").
+		Pre("if a < b {\n}", "go").
+		Text("\n").
+		Blockquote("quoted\nlines")
+
+	return b.Build()
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	text, entities := sampleFormattedText()
+
+	for _, parseMode := range []string{TgParseModMarkdownV2, TgParseModMarkdownHTML} {
+		rendered := Render(parseMode, text, entities)
+
+		gotText, gotEntities, err := Parse(parseMode, rendered)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", parseMode, err, rendered)
+		}
+
+		if gotText != text {
+			t.Errorf("%s: text = %q, want %q", parseMode, gotText, text)
+		}
+
+		if !reflect.DeepEqual(gotEntities, entities) {
+			t.Errorf("%s: entities = %+v\nwant %+v\nrendered %q", parseMode, gotEntities, entities, rendered)
+		}
+	}
+}
+
+func TestRenderExpandableBlockquoteEndingInNewline(t *testing.T) {
+	text := "ab\ncd\nrest"
+	entities := []MessageEntity{{Type: EntityTypeExpandableBlockquote, Offset: 0, Length: 6}}
+
+	rendered := RenderMarkdownV2(text, entities)
+	if rendered != "**>ab\n>cd||\nrest" {
+		t.Errorf("RenderMarkdownV2() = %q", rendered)
+	}
+
+	// Like Telegram, the parser leaves the trailing line break out of the quote.
+	gotText, gotEntities, err := ParseMarkdownV2(rendered)
+	want := []MessageEntity{{Type: EntityTypeExpandableBlockquote, Offset: 0, Length: 5}}
+	if err != nil || gotText != text || !reflect.DeepEqual(gotEntities, want) {
+		t.Errorf("ParseMarkdownV2(%q) = %q, %+v, %v", rendered, gotText, gotEntities, err)
+	}
+
+	if again := RenderMarkdownV2(gotText, gotEntities); again != rendered {
+		t.Errorf("rendering the parsed text = %q, want %q", again, rendered)
+	}
+}
+
+func TestRenderCodeInsideSameRangeEntities(t *testing.T) {
+	const input = "*`code`* and ||`x`||"
+
+	// The parser lists code before the bold and spoiler around it.
+	text, entities, err := ParseMarkdownV2(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rendered := RenderMarkdownV2(text, entities); rendered != input {
+		t.Errorf("RenderMarkdownV2() = %q, want %q", rendered, input)
+	}
+
+	for _, parseMode := range []string{TgParseModMarkdownV2, TgParseModMarkdownHTML} {
+		rendered := Render(parseMode, text, entities)
+
+		gotText, gotEntities, err := Parse(parseMode, rendered)
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", parseMode, err, rendered)
+		}
+
+		if gotText != text || !sameEntities(gotEntities, entities) {
+			t.Errorf("%s: Parse(%q) = %q, %+v\nwant %q, %+v", parseMode, rendered, gotText, gotEntities, text, entities)
+		}
+	}
+}
+
+func TestRenderMarkdownV2(t *testing.T) {
+	text := "italic underline"
+	entities := []MessageEntity{
+		{Type: EntityTypeItalic, Offset: 0, Length: 16},
+		{Type: EntityTypeUnderline, Offset: 7, Length: 9},
+	}
+
+	got := RenderMarkdownV2(text, entities)
+	if got != "_italic __underline__\r_" {
+		t.Errorf("RenderMarkdownV2() = %q", got)
+	}
+
+	gotText, gotEntities, err := ParseMarkdownV2(got)
+	if err != nil || gotText != text || !reflect.DeepEqual(gotEntities, entities) {
+		t.Errorf("ParseMarkdownV2(%q) = %q, %+v, %v", got, gotText, gotEntities, err)
+	}
+}
+
+func TestRenderOverlappingEntities(t *testing.T) {
+	got := RenderHTML("abcdef", []MessageEntity{
+		{Type: EntityTypeBold, Offset: 0, Length: 4},
+		{Type: EntityTypeItalic, Offset: 2, Length: 4},
+	})
+
+	if got != "<b>ab<i>cd</i></b><i>ef</i>" {
+		t.Errorf("RenderHTML() = %q", got)
+	}
+}
+
+func TestCommonMark(t *testing.T) {
+	source := "# Match day\n\n**Arsenal** beat *Chelsea* ~~3-0~~ 2\\-1, see [report](https://example.com/a) and `code`.\n\n" +
+		"> quoted *line*\n\n```go\nx := 1\n```\nsnake_case_name"
+
+	text, entities := ParseCommonMark(source)
+
+	wantText := "Match day\n\nArsenal beat Chelsea 3-0 2-1, see report and code.\n\nquoted line\n\nx := 1\nsnake_case_name"
+	if text != wantText {
+		t.Fatalf("text = %q, want %q", text, wantText)
+	}
+
+	want := []MessageEntity{
+		{Type: EntityTypeBold, Offset: 0, Length: 9},
+		{Type: EntityTypeBold, Offset: 11, Length: 7},
+		{Type: EntityTypeItalic, Offset: 24, Length: 7},
+		{Type: EntityTypeStrikethrough, Offset: 32, Length: 3},
+		{Type: EntityTypeTextLink, Offset: 45, Length: 6, URL: "https://example.com/a"},
+		{Type: EntityTypeCode, Offset: 56, Length: 4},
+		{Type: EntityTypeBlockquote, Offset: 63, Length: 11},
+		{Type: EntityTypeItalic, Offset: 70, Length: 4},
+		{Type: EntityTypePre, Offset: 76, Length: 6, Language: "go"},
+	}
+
+	if !reflect.DeepEqual(entities, want) {
+		t.Errorf("entities = %+v\nwant %+v", entities, want)
+	}
+
+	rendered := RenderCommonMark(text, entities)
+	gotText, gotEntities := ParseCommonMark(rendered)
+	if gotText != text || !reflect.DeepEqual(gotEntities, entities) {
+		t.Errorf("round trip of %q = %q, %+v", rendered, gotText, gotEntities)
+	}
+}
+
+func TestMessage_FormattedText(t *testing.T) {
+	message := Message{
+		Caption:         "Goal!",
+		CaptionEntities: []MessageEntity{{Type: EntityTypeBold, Offset: 0, Length: 4}},
+	}
+
+	if got := message.FormattedText(TgParseModMarkdownV2); got != "*Goal*\\!" {
+		t.Errorf("FormattedText() = %q", got)
+	}
+}
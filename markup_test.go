@@ -0,0 +1,204 @@
+package teledau
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		parseMode string
+		in        string
+		want      string
+	}{
+		{TgParseModMarkdownV2, "Arsenal 2-1 (f.t.)!", "Arsenal 2\\-1 \\(f\\.t\\.\\)\\!"},
+		{TgParseModMarkdownV2, "a\\b_c", "a\\\\b\\_c"},
+		{TgParseModMarkdownV1, "snake_case *x*", "snake\\_case \\*x\\*"},
+		{TgParseModMarkdownHTML, "<b>&\"", "&lt;b&gt;&amp;&quot;"},
+		{"", "*as is*", "*as is*"},
+	}
+
+	for _, tt := range tests {
+		if got := Escape(tt.parseMode, tt.in); got != tt.want {
+			t.Errorf("Escape(%q, %q) = %q, want %q", tt.parseMode, tt.in, got, tt.want)
+		}
+
+		if err := Validate(tt.parseMode, Escape(tt.parseMode, tt.in)); err != nil {
+			t.Errorf("escaped %q does not validate: %v", tt.in, err)
+		}
+	}
+}
+
+func TestSprintf(t *testing.T) {
+	got := Sprintf(TgParseModMarkdownV2, "*%s* scored in the %d\\. minute %s %5.1f", "J. Smith-Rowe", 90, Markup("_wow_"), 1.5)
+	want := "*J\\. Smith\\-Rowe* scored in the 90\\. minute _wow_   1\\.5"
+	if got != want {
+		t.Errorf("Sprintf() = %q, want %q", got, want)
+	}
+
+	if err := ValidateMarkdownV2(got); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewTemplate(t *testing.T) {
+	tmpl, err := NewTemplate("card", TgParseModMarkdownHTML,
+		`<b>{{.Home}}</b> {{.Score}} <b>{{.Away}}</b>{{range .Scorers}}
+• {{.}}{{end}}{{with .Link}}
+{{raw .}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	err = tmpl.Execute(&out, map[string]interface{}{
+		"Home":    "Brighton & Hove",
+		"Away":    "<Chelsea>",
+		"Score":   "2:1",
+		"Scorers": []string{"A & B"},
+		"Link":    `<a href="https://example.com">report</a>`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "<b>Brighton &amp; Hove</b> 2:1 <b>&lt;Chelsea&gt;</b>\n• A &amp; B\n<a href=\"https://example.com\">report</a>"
+	if out.String() != want {
+		t.Errorf("template output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestParseMarkdownV2(t *testing.T) {
+	text, entities, err := ParseMarkdownV2("*bold _italic ~strike~_* __under__ ||spoiler|| [link](https://example.com/a\\)b) " +
+		"[user](tg://user?id=42) ![👍](tg://emoji?id=5368324170671202286) `co\\`de` ```go\nfmt.Println()\n```\n>quote\n>line\nend")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantText := "bold italic strike under spoiler link user 👍 co`de fmt.Println()\n\nquote\nline\nend"
+	if text != wantText {
+		t.Fatalf("text = %q, want %q", text, wantText)
+	}
+
+	want := []MessageEntity{
+		{Type: EntityTypeBold, Offset: 0, Length: 18},
+		{Type: EntityTypeItalic, Offset: 5, Length: 13},
+		{Type: EntityTypeStrikethrough, Offset: 12, Length: 6},
+		{Type: EntityTypeUnderline, Offset: 19, Length: 5},
+		{Type: EntityTypeSpoiler, Offset: 25, Length: 7},
+		{Type: EntityTypeTextLink, Offset: 33, Length: 4, URL: "https://example.com/a)b"},
+		{Type: EntityTypeTextMention, Offset: 38, Length: 4, User: &From{Id: 42}},
+		{Type: EntityTypeCustomEmoji, Offset: 43, Length: 2, CustomEmojiId: "5368324170671202286"},
+		{Type: EntityTypeCode, Offset: 46, Length: 5},
+		{Type: EntityTypePre, Offset: 52, Length: 14, Language: "go"},
+		{Type: EntityTypeBlockquote, Offset: 67, Length: 10},
+	}
+
+	if !reflect.DeepEqual(entities, want) {
+		t.Errorf("entities = %+v\nwant %+v", entities, want)
+	}
+}
+
+func TestParseMarkdownV2Errors(t *testing.T) {
+	tests := []struct {
+		text   string
+		offset int
+		msg    string
+	}{
+		{"Final score 2-1", 13, "Character '-' is reserved"},
+		{"Well done.", 9, "Character '.' is reserved"},
+		{"*unclosed", 0, "Can't find end of Bold entity"},
+		{"[link](https://example.com", 7, "Can't find end of a URL"},
+		{"single | pipe", 7, "Character '|' is reserved"},
+	}
+
+	for _, tt := range tests {
+		err := ValidateMarkdownV2(tt.text)
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("ValidateMarkdownV2(%q) = %v, want *ParseError", tt.text, err)
+
+			continue
+		}
+
+		if parseErr.Offset != tt.offset || !strings.Contains(parseErr.Message, tt.msg) {
+			t.Errorf("ValidateMarkdownV2(%q) = %q at %d, want %q at %d", tt.text, parseErr.Message, parseErr.Offset, tt.msg, tt.offset)
+		}
+	}
+}
+
+func TestParseHTML(t *testing.T) {
+	text, entities, err := ParseHTML(`<b>bold <i>italic</i></b> <a href="https://example.com?a=1&amp;b=2">link</a> ` +
+		`<span class="tg-spoiler">s</span> <pre><code class="language-go">x := 1</code></pre> ` +
+		`<blockquote expandable>q</blockquote> 1 &lt; 2 &#128077;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantText := "bold italic link s x := 1 q 1 < 2 👍"
+	if text != wantText {
+		t.Fatalf("text = %q, want %q", text, wantText)
+	}
+
+	want := []MessageEntity{
+		{Type: EntityTypeBold, Offset: 0, Length: 11},
+		{Type: EntityTypeItalic, Offset: 5, Length: 6},
+		{Type: EntityTypeTextLink, Offset: 12, Length: 4, URL: "https://example.com?a=1&b=2"},
+		{Type: EntityTypeSpoiler, Offset: 17, Length: 1},
+		{Type: EntityTypePre, Offset: 19, Length: 6, Language: "go"},
+		{Type: EntityTypeExpandableBlockquote, Offset: 26, Length: 1},
+	}
+
+	if !reflect.DeepEqual(entities, want) {
+		t.Errorf("entities = %+v\nwant %+v", entities, want)
+	}
+}
+
+func TestParseHTMLErrors(t *testing.T) {
+	tests := []struct {
+		text string
+		msg  string
+	}{
+		{"1 < 2", `Unsupported start tag ""`},
+		{"<b>bold</i>", `Unmatched end tag at byte offset 7, expected "</b>", found "</i>"`},
+		{"<b>bold", `Can't find end tag corresponding to start tag "b"`},
+		{"<div>x</div>", `Unsupported start tag "div"`},
+		{"<span>x</span>", `Tag "span" must have class "tg-spoiler"`},
+	}
+
+	for _, tt := range tests {
+		err := ValidateHTML(tt.text)
+		if err == nil || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("ValidateHTML(%q) = %v, want %q", tt.text, err, tt.msg)
+		}
+	}
+}
+
+func TestParseMarkdown(t *testing.T) {
+	text, entities, err := ParseMarkdown("*bold* _it_ [link](https://example.com) `code` snake\\_case")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if text != "bold it link code snake_case" {
+		t.Fatalf("text = %q", text)
+	}
+
+	want := []MessageEntity{
+		{Type: EntityTypeBold, Offset: 0, Length: 4},
+		{Type: EntityTypeItalic, Offset: 5, Length: 2},
+		{Type: EntityTypeTextLink, Offset: 8, Length: 4, URL: "https://example.com"},
+		{Type: EntityTypeCode, Offset: 13, Length: 4},
+	}
+
+	if !reflect.DeepEqual(entities, want) {
+		t.Errorf("entities = %+v\nwant %+v", entities, want)
+	}
+
+	if err := Validate(TgParseModMarkdownV1, "snake_case"); err == nil {
+		t.Error("expected an error for an unclosed italic entity")
+	}
+}
@@ -1,41 +1,92 @@
 package teledau
 
+import (
+	"encoding/json"
+	"strconv"
+)
+
 type Channel struct {
 	Id       int
 	ChatID   string
 	Username string
 }
 
+// PollRequest sends a poll. Options are plain texts; use PollOptions instead for formatted ones.
+// CorrectOptionId is only sent for PollTypeQuiz polls, where it is required.
 type PollRequest struct {
-	ChatId          string   `json:"chat_id"`
-	Question        string   `json:"question"`
-	Options         []string `json:"options"`
-	IsAnonymous     bool     `json:"is_anonymous"`
-	Type            string   `json:"type"`
-	CorrectOptionId int      `json:"correct_option_id"`
-	Explanation     string   `json:"explanation"`
-	OpenPeriod      int      `json:"open_period"`
-	CloseDate       int      `json:"close_date"`
+	ChatId                string            `json:"chat_id"`
+	Question              string            `json:"question"`
+	QuestionParseMode     string            `json:"question_parse_mode,omitempty"`
+	QuestionEntities      []MessageEntity   `json:"question_entities,omitempty"`
+	Options               []string          `json:"-"`
+	PollOptions           []InputPollOption `json:"-"`
+	IsAnonymous           bool              `json:"is_anonymous"`
+	Type                  string            `json:"type"`
+	AllowsMultipleAnswers bool              `json:"allows_multiple_answers,omitempty"`
+	CorrectOptionId       int               `json:"-"`
+	Explanation           string            `json:"explanation,omitempty"`
+	ExplanationParseMode  string            `json:"explanation_parse_mode,omitempty"`
+	ExplanationEntities   []MessageEntity   `json:"explanation_entities,omitempty"`
+	OpenPeriod            int               `json:"open_period,omitempty"`
+	CloseDate             int               `json:"close_date,omitempty"`
+	IsClosed              bool              `json:"is_closed,omitempty"`
+
+	MessageThreadId     int              `json:"message_thread_id,omitempty"`
+	DisableNotification bool             `json:"disable_notification,omitempty"`
+	ProtectContent      bool             `json:"protect_content,omitempty"`
+	MessageEffectId     string           `json:"message_effect_id,omitempty"`
+	ReplyParameters     *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyMarkup         interface{}      `json:"reply_markup,omitempty"`
+}
+
+// MarshalJSON sends Options as InputPollOptions and leaves out correct_option_id for regular polls.
+func (p PollRequest) MarshalJSON() ([]byte, error) {
+	type pollRequest PollRequest
+
+	options := p.PollOptions
+	if len(options) == 0 {
+		options = make([]InputPollOption, len(p.Options))
+		for i, text := range p.Options {
+			options[i] = InputPollOption{Text: text}
+		}
+	}
+
+	var correctOptionId *int
+	if p.Type == PollTypeQuiz {
+		correctOptionId = &p.CorrectOptionId
+	}
+
+	return json.Marshal(struct {
+		pollRequest
+		Options         []InputPollOption `json:"options"`
+		CorrectOptionId *int              `json:"correct_option_id,omitempty"`
+	}{pollRequest(p), options, correctOptionId})
+}
+
+type InputPollOption struct {
+	Text          string          `json:"text"`
+	TextParseMode string          `json:"text_parse_mode,omitempty"`
+	TextEntities  []MessageEntity `json:"text_entities,omitempty"`
 }
 
 type StikerResponse struct {
-	Ok     bool   `json:"ok"`
-	Result Result `json:"result"`
+	Ok     bool    `json:"ok"`
+	Result Message `json:"result"`
 }
 
 type PostResponse struct {
-	Ok     bool   `json:"ok"`
-	Result Result `json:"result"`
+	Ok     bool    `json:"ok"`
+	Result Message `json:"result"`
 }
 
 type MediaPostResponse struct {
-	Ok     bool     `json:"ok"`
-	Result []Result `json:"result"`
+	Ok     bool      `json:"ok"`
+	Result []Message `json:"result"`
 }
 
 type PollResponse struct {
-	Ok     bool   `json:"ok"`
-	Result Result `json:"result"`
+	Ok     bool    `json:"ok"`
+	Result Message `json:"result"`
 }
 
 type MessageId struct {
@@ -51,19 +102,19 @@ type Post struct {
 }
 
 type MediaGroup struct {
-	Type            string            `json:"type"`
-	Media           string            `json:"media"`
-	Caption         string            `json:"caption,omitempty"`
-	CaptionEntities []CaptionEntities `json:"caption_entities,omitempty"`
-	ParseMode       string            `json:"parse_mode,omitempty"`
+	Type            string          `json:"type"`
+	Media           string          `json:"media"`
+	Caption         string          `json:"caption,omitempty"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	ParseMode       string          `json:"parse_mode,omitempty"`
 }
 
-type CaptionEntities struct {
-	Offset int    `json:"offset"`
-	Length int    `json:"length"`
-	Type   string `json:"type"`
-}
+// CaptionEntities is the former name of MessageEntity.
+type CaptionEntities = MessageEntity
 
+// PostRequest is a channel post published by PostScheduler. PostType is one of the PostType*
+// constants and Status one of the PostStatus* constants; PostedAt is the RFC 3339 publication time.
+// Img holds base64-encoded images.
 type PostRequest struct {
 	Id               int64    `json:"id"`
 	Title            string   `json:"title"`
@@ -77,36 +128,57 @@ type PostRequest struct {
 	Status           int64    `json:"status"`
 	PostedAt         string   `json:"posted_at"`
 	ReplyToMessageId int64    `json:"reply_to_message_id"`
+
+	// MessageIds are all messages of a published post; MessageId is the first of them.
+	// While a failed post waits for a retry they are the messages already sent.
+	MessageIds    []int64 `json:"message_ids,omitempty"`
+	Attempts      int     `json:"attempts,omitempty"`
+	LastError     string  `json:"last_error,omitempty"`
+	NextAttemptAt string  `json:"next_attempt_at,omitempty"`
+	PublishedAt   string  `json:"published_at,omitempty"`
+}
+
+// ChatId returns the chat the post is published to: ChatUsername, or ChannelId when it is empty.
+func (p PostRequest) ChatId() string {
+	if len(p.ChatUsername) > 0 {
+		return p.ChatUsername
+	}
+
+	return strconv.Itoa(p.ChannelId)
+}
+
+// ReplyParameters returns the reply_parameters for ReplyToMessageId, or nil when the post is not a reply.
+func (p PostRequest) ReplyParameters() *ReplyParameters {
+	if p.ReplyToMessageId == 0 {
+		return nil
+	}
+
+	return &ReplyParameters{MessageId: int(p.ReplyToMessageId), AllowSendingWithoutReply: true}
 }
 
 type Update struct {
-	UpdateId   int         `json:"update_id"`
-	ChatMember *ChatMember `json:"chat_member,omitempty"`
-	Message    *Message    `json:"message,omitempty"`
+	UpdateId             int                          `json:"update_id"`
+	ChatMember           *ChatMember                  `json:"chat_member,omitempty"`
+	Message              *Message                     `json:"message,omitempty"`
+	EditedMessage        *Message                     `json:"edited_message,omitempty"`
+	ChannelPost          *Message                     `json:"channel_post,omitempty"`
+	EditedChannelPost    *Message                     `json:"edited_channel_post,omitempty"`
+	BusinessMessage      *Message                     `json:"business_message,omitempty"`
+	MyChatMember         *ChatMember                  `json:"my_chat_member,omitempty"`
+	ChatJoinRequest      *ChatJoinRequest             `json:"chat_join_request,omitempty"`
+	InlineQuery          *InlineQuery                 `json:"inline_query,omitempty"`
+	ChosenInlineResult   *ChosenInlineResult          `json:"chosen_inline_result,omitempty"`
+	Poll                 *Poll                        `json:"poll,omitempty"`
+	PollAnswer           *PollAnswer                  `json:"poll_answer,omitempty"`
+	MessageReaction      *MessageReactionUpdated      `json:"message_reaction,omitempty"`
+	MessageReactionCount *MessageReactionCountUpdated `json:"message_reaction_count,omitempty"`
+	ShippingQuery        *ShippingQuery               `json:"shipping_query,omitempty"`
+	PreCheckoutQuery     *PreCheckoutQuery            `json:"pre_checkout_query,omitempty"`
 }
 
 type SendMessageResponse struct {
-	Ok     bool   `json:"ok"`
-	Result Result `json:"result"`
-}
-
-type Result struct {
-	MessageId          int        `json:"message_id"`
-	From               From       `json:"from"`
-	Chat               Chat       `json:"chat"`
-	SenderChat         Chat       `json:"sender_chat"`
-	Date               int        `json:"date"`
-	Text               string     `json:"text"`
-	Photo              []Photo    `json:"photo"`
-	Sticker            Sticker    `json:"sticker"`
-	Entities           []Entities `json:"entities"`
-	Poll               Poll       `json:"poll"`
-	InviteLink         string     `json:"invite_link"`
-	Name               string     `json:"name"`
-	Creator            Creator    `json:"creator"`
-	CreatesJoinRequest bool       `json:"creates_join_request"`
-	IsPrimary          bool       `json:"is_primary"`
-	IsRevoked          bool       `json:"is_revoked"`
+	Ok     bool    `json:"ok"`
+	Result Message `json:"result"`
 }
 
 type Creator struct {
@@ -117,32 +189,43 @@ type Creator struct {
 }
 
 type Poll struct {
-	Id                    string        `json:"id"`
-	Question              string        `json:"question"`
-	Options               []Options     `json:"options"`
-	TotalVoterCount       int           `json:"total_voter_count"`
-	OpenPeriod            int           `json:"open_period"`
-	CloseDate             int           `json:"close_date"`
-	IsClosed              bool          `json:"is_closed"`
-	IsAnonymous           bool          `json:"is_anonymous"`
-	Type                  string        `json:"type"`
-	AllowsMultipleAnswers bool          `json:"allows_multiple_answers"`
-	CorrectOptionId       int           `json:"correct_option_id"`
-	Explanation           string        `json:"explanation"`
-	ExplanationEntities   []interface{} `json:"explanation_entities"`
+	Id                    string          `json:"id"`
+	Question              string          `json:"question"`
+	QuestionEntities      []MessageEntity `json:"question_entities,omitempty"`
+	Options               []Options       `json:"options"`
+	TotalVoterCount       int             `json:"total_voter_count"`
+	OpenPeriod            int             `json:"open_period"`
+	CloseDate             int             `json:"close_date"`
+	IsClosed              bool            `json:"is_closed"`
+	IsAnonymous           bool            `json:"is_anonymous"`
+	Type                  string          `json:"type"`
+	AllowsMultipleAnswers bool            `json:"allows_multiple_answers"`
+	CorrectOptionId       int             `json:"correct_option_id"`
+	Explanation           string          `json:"explanation"`
+	ExplanationEntities   []MessageEntity `json:"explanation_entities"`
 }
 
 type Options struct {
-	Text       string `json:"text"`
-	VoterCount int    `json:"voter_count"`
+	Text         string          `json:"text"`
+	TextEntities []MessageEntity `json:"text_entities,omitempty"`
+	VoterCount   int             `json:"voter_count"`
 }
 
-type Entities struct {
-	Length int    `json:"length"`
-	Offset int    `json:"offset"`
-	Type   string `json:"type"`
+// MessageEntity describes a formatted part of a text or caption.
+// Offset and Length are measured in UTF-16 code units.
+type MessageEntity struct {
+	Type          string `json:"type"`
+	Offset        int    `json:"offset"`
+	Length        int    `json:"length"`
+	URL           string `json:"url,omitempty"`
+	User          *From  `json:"user,omitempty"`
+	Language      string `json:"language,omitempty"`
+	CustomEmojiId string `json:"custom_emoji_id,omitempty"`
 }
 
+// Entities is the former name of MessageEntity.
+type Entities = MessageEntity
+
 type From struct {
 	Id           int64  `json:"id"`
 	IsBot        bool   `json:"is_bot"`
@@ -179,23 +262,186 @@ type Sticker struct {
 	FileId       string    `json:"file_id"`
 	FileUniqueId string    `json:"file_unique_id"`
 	FileSize     int       `json:"file_size"`
+
+	Emoji            string        `json:"emoji,omitempty"`
+	SetName          string        `json:"set_name,omitempty"`
+	CustomEmojiId    string        `json:"custom_emoji_id,omitempty"`
+	PremiumAnimation *File         `json:"premium_animation,omitempty"`
+	MaskPosition     *MaskPosition `json:"mask_position,omitempty"`
+	NeedsRepainting  bool          `json:"needs_repainting,omitempty"`
 }
 
 type Chat struct {
 	Id        int    `json:"id"`
+	Title     string `json:"title,omitempty"`
 	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
 	Username  string `json:"username"`
 	Type      string `json:"type"`
+	IsForum   bool   `json:"is_forum,omitempty"`
 }
 
 type Message struct {
-	MessageId      int             `json:"message_id"`
-	From           From            `json:"from"`
-	Chat           Chat            `json:"chat"`
-	Date           int             `json:"date"`
-	Text           string          `json:"text"`
-	ReplyToMessage *ReplyToMessage `json:"reply_to_message,omitempty"`
-	Photo          []Photo         `json:"photo"`
+	MessageId             int                   `json:"message_id"`
+	MessageThreadId       int                   `json:"message_thread_id,omitempty"`
+	From                  From                  `json:"from"`
+	SenderChat            *Chat                 `json:"sender_chat,omitempty"`
+	Date                  int                   `json:"date"`
+	BusinessConnectionId  string                `json:"business_connection_id,omitempty"`
+	Chat                  Chat                  `json:"chat"`
+	ForwardOrigin         *MessageOrigin        `json:"forward_origin,omitempty"`
+	IsTopicMessage        bool                  `json:"is_topic_message,omitempty"`
+	IsAutomaticForward    bool                  `json:"is_automatic_forward,omitempty"`
+	ReplyToMessage        *Message              `json:"reply_to_message,omitempty"`
+	ViaBot                *From                 `json:"via_bot,omitempty"`
+	EditDate              int                   `json:"edit_date,omitempty"`
+	HasProtectedContent   bool                  `json:"has_protected_content,omitempty"`
+	MediaGroupId          string                `json:"media_group_id,omitempty"`
+	AuthorSignature       string                `json:"author_signature,omitempty"`
+	Text                  string                `json:"text,omitempty"`
+	Entities              []MessageEntity       `json:"entities,omitempty"`
+	EffectId              string                `json:"effect_id,omitempty"`
+	Animation             *Animation            `json:"animation,omitempty"`
+	Audio                 *Audio                `json:"audio,omitempty"`
+	Document              *Document             `json:"document,omitempty"`
+	Photo                 []Photo               `json:"photo,omitempty"`
+	Sticker               *Sticker              `json:"sticker,omitempty"`
+	Video                 *Video                `json:"video,omitempty"`
+	VideoNote             *VideoNote            `json:"video_note,omitempty"`
+	Voice                 *Voice                `json:"voice,omitempty"`
+	Caption               string                `json:"caption,omitempty"`
+	CaptionEntities       []MessageEntity       `json:"caption_entities,omitempty"`
+	HasMediaSpoiler       bool                  `json:"has_media_spoiler,omitempty"`
+	Contact               *Contact              `json:"contact,omitempty"`
+	Dice                  *Dice                 `json:"dice,omitempty"`
+	Poll                  *Poll                 `json:"poll,omitempty"`
+	Venue                 *Venue                `json:"venue,omitempty"`
+	Location              *Location             `json:"location,omitempty"`
+	NewChatMembers        []From                `json:"new_chat_members,omitempty"`
+	LeftChatMember        *From                 `json:"left_chat_member,omitempty"`
+	NewChatTitle          string                `json:"new_chat_title,omitempty"`
+	NewChatPhoto          []Photo               `json:"new_chat_photo,omitempty"`
+	DeleteChatPhoto       bool                  `json:"delete_chat_photo,omitempty"`
+	GroupChatCreated      bool                  `json:"group_chat_created,omitempty"`
+	SupergroupChatCreated bool                  `json:"supergroup_chat_created,omitempty"`
+	ChannelChatCreated    bool                  `json:"channel_chat_created,omitempty"`
+	MigrateToChatId       int64                 `json:"migrate_to_chat_id,omitempty"`
+	MigrateFromChatId     int64                 `json:"migrate_from_chat_id,omitempty"`
+	PinnedMessage         *Message              `json:"pinned_message,omitempty"`
+	ForumTopicCreated     *ForumTopicCreated    `json:"forum_topic_created,omitempty"`
+	ForumTopicEdited      *ForumTopicEdited     `json:"forum_topic_edited,omitempty"`
+	ForumTopicClosed      *struct{}             `json:"forum_topic_closed,omitempty"`
+	ForumTopicReopened    *struct{}             `json:"forum_topic_reopened,omitempty"`
+	ReplyMarkup           *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+}
+
+// MessageOrigin describes where a forwarded message came from.
+// Type is one of "user", "hidden_user", "chat" or "channel" and decides which fields are set.
+type MessageOrigin struct {
+	Type            string `json:"type"`
+	Date            int    `json:"date"`
+	SenderUser      *From  `json:"sender_user,omitempty"`
+	SenderUserName  string `json:"sender_user_name,omitempty"`
+	SenderChat      *Chat  `json:"sender_chat,omitempty"`
+	Chat            *Chat  `json:"chat,omitempty"`
+	MessageId       int    `json:"message_id,omitempty"`
+	AuthorSignature string `json:"author_signature,omitempty"`
+}
+
+type Animation struct {
+	FileId       string     `json:"file_id"`
+	FileUniqueId string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *Thumbnail `json:"thumbnail,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+type Audio struct {
+	FileId       string     `json:"file_id"`
+	FileUniqueId string     `json:"file_unique_id"`
+	Duration     int        `json:"duration"`
+	Performer    string     `json:"performer,omitempty"`
+	Title        string     `json:"title,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+	Thumbnail    *Thumbnail `json:"thumbnail,omitempty"`
+}
+
+type Document struct {
+	FileId       string     `json:"file_id"`
+	FileUniqueId string     `json:"file_unique_id"`
+	Thumbnail    *Thumbnail `json:"thumbnail,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+type Video struct {
+	FileId       string     `json:"file_id"`
+	FileUniqueId string     `json:"file_unique_id"`
+	Width        int        `json:"width"`
+	Height       int        `json:"height"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *Thumbnail `json:"thumbnail,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+type VideoNote struct {
+	FileId       string     `json:"file_id"`
+	FileUniqueId string     `json:"file_unique_id"`
+	Length       int        `json:"length"`
+	Duration     int        `json:"duration"`
+	Thumbnail    *Thumbnail `json:"thumbnail,omitempty"`
+	FileSize     int64      `json:"file_size,omitempty"`
+}
+
+type Voice struct {
+	FileId       string `json:"file_id"`
+	FileUniqueId string `json:"file_unique_id"`
+	Duration     int    `json:"duration"`
+	MimeType     string `json:"mime_type,omitempty"`
+	FileSize     int64  `json:"file_size,omitempty"`
+}
+
+type Contact struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	UserId      int64  `json:"user_id,omitempty"`
+	Vcard       string `json:"vcard,omitempty"`
+}
+
+type Dice struct {
+	Emoji string `json:"emoji"`
+	Value int    `json:"value"`
+}
+
+type Venue struct {
+	Location        Location `json:"location"`
+	Title           string   `json:"title"`
+	Address         string   `json:"address"`
+	FoursquareId    string   `json:"foursquare_id,omitempty"`
+	FoursquareType  string   `json:"foursquare_type,omitempty"`
+	GooglePlaceId   string   `json:"google_place_id,omitempty"`
+	GooglePlaceType string   `json:"google_place_type,omitempty"`
+}
+
+type ForumTopicCreated struct {
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiId string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+type ForumTopicEdited struct {
+	Name              string `json:"name,omitempty"`
+	IconCustomEmojiId string `json:"icon_custom_emoji_id,omitempty"`
 }
 
 type Photo struct {
@@ -206,13 +452,8 @@ type Photo struct {
 	Height       int    `json:"height"`
 }
 
-type ReplyToMessage struct {
-	MessageId int    `json:"message_id"`
-	From      From   `json:"from"`
-	Chat      Chat   `json:"chat"`
-	Date      int    `json:"date"`
-	Text      string `json:"text"`
-}
+// ReplyToMessage is the former name of the Message.ReplyToMessage type.
+type ReplyToMessage = Message
 
 type ChatMember struct {
 	Chat          Chat          `json:"chat"`
@@ -223,23 +464,61 @@ type ChatMember struct {
 	InviteLink    InviteLink    `json:"invite_link,omitempty"`
 }
 
+// NewChatMember is a ChatMember object of the Bot API: a user's status in a chat
+// together with the rights or restrictions that status carries.
 type NewChatMember struct {
-	User   From   `json:"user"`
-	Status string `json:"status"`
+	User        From   `json:"user"`
+	Status      string `json:"status"`
+	CustomTitle string `json:"custom_title,omitempty"`
+	IsAnonymous bool   `json:"is_anonymous,omitempty"`
+	UntilDate   int64  `json:"until_date,omitempty"`
+	IsMember    bool   `json:"is_member,omitempty"`
+
+	CanBeEdited         bool `json:"can_be_edited,omitempty"`
+	CanManageChat       bool `json:"can_manage_chat,omitempty"`
+	CanDeleteMessages   bool `json:"can_delete_messages,omitempty"`
+	CanManageVideoChats bool `json:"can_manage_video_chats,omitempty"`
+	CanRestrictMembers  bool `json:"can_restrict_members,omitempty"`
+	CanPromoteMembers   bool `json:"can_promote_members,omitempty"`
+	CanPostStories      bool `json:"can_post_stories,omitempty"`
+	CanEditStories      bool `json:"can_edit_stories,omitempty"`
+	CanDeleteStories    bool `json:"can_delete_stories,omitempty"`
+	CanPostMessages     bool `json:"can_post_messages,omitempty"`
+	CanEditMessages     bool `json:"can_edit_messages,omitempty"`
+
+	CanSendMessages       bool `json:"can_send_messages,omitempty"`
+	CanSendAudios         bool `json:"can_send_audios,omitempty"`
+	CanSendDocuments      bool `json:"can_send_documents,omitempty"`
+	CanSendPhotos         bool `json:"can_send_photos,omitempty"`
+	CanSendVideos         bool `json:"can_send_videos,omitempty"`
+	CanSendVideoNotes     bool `json:"can_send_video_notes,omitempty"`
+	CanSendVoiceNotes     bool `json:"can_send_voice_notes,omitempty"`
+	CanSendPolls          bool `json:"can_send_polls,omitempty"`
+	CanSendOtherMessages  bool `json:"can_send_other_messages,omitempty"`
+	CanAddWebPagePreviews bool `json:"can_add_web_page_previews,omitempty"`
+	CanChangeInfo         bool `json:"can_change_info,omitempty"`
+	CanInviteUsers        bool `json:"can_invite_users,omitempty"`
+	CanPinMessages        bool `json:"can_pin_messages,omitempty"`
+	CanManageTopics       bool `json:"can_manage_topics,omitempty"`
 }
 
 type InviteLink struct {
-	InviteLink         string  `json:"invite_link"`
-	Name               string  `json:"name"`
-	Creator            Creator `json:"creator"`
-	CreatesJoinRequest bool    `json:"creates_join_request"`
-	IsPrimary          bool    `json:"is_primary"`
-	IsRevoked          bool    `json:"is_revoked"`
+	InviteLink              string  `json:"invite_link"`
+	Name                    string  `json:"name"`
+	Creator                 Creator `json:"creator"`
+	CreatesJoinRequest      bool    `json:"creates_join_request"`
+	IsPrimary               bool    `json:"is_primary"`
+	IsRevoked               bool    `json:"is_revoked"`
+	ExpireDate              int64   `json:"expire_date,omitempty"`
+	MemberLimit             int     `json:"member_limit,omitempty"`
+	PendingJoinRequestCount int     `json:"pending_join_request_count,omitempty"`
+	SubscriptionPeriod      int     `json:"subscription_period,omitempty"`
+	SubscriptionPrice       int     `json:"subscription_price,omitempty"`
 }
 
 type InviteLinks struct {
-	Ok     bool   `json:"ok"`
-	Result Result `json:"result"`
+	Ok     bool       `json:"ok"`
+	Result InviteLink `json:"result"`
 }
 
 type MediaResponse struct {
@@ -250,6 +529,8 @@ type GetChatResponse struct {
 	Ok      bool    `json:"ok"`
 	GetChat GetChat `json:"result"`
 }
+
+// GetChat is the ChatFullInfo object returned by getChat.
 type GetChat struct {
 	Id                int64      `json:"id"`
 	Title             string     `json:"title"`
@@ -262,6 +543,40 @@ type GetChat struct {
 	Photo             *ChatPhoto `json:"photo"`
 	MaxReactionCount  int        `json:"max_reaction_count"`
 	AccentColorId     int        `json:"accent_color_id"`
+
+	FirstName                          string           `json:"first_name,omitempty"`
+	LastName                           string           `json:"last_name,omitempty"`
+	IsForum                            bool             `json:"is_forum,omitempty"`
+	Bio                                string           `json:"bio,omitempty"`
+	HasPrivateForwards                 bool             `json:"has_private_forwards,omitempty"`
+	HasRestrictedVoiceAndVideoMessages bool             `json:"has_restricted_voice_and_video_messages,omitempty"`
+	JoinToSendMessages                 bool             `json:"join_to_send_messages,omitempty"`
+	JoinByRequest                      bool             `json:"join_by_request,omitempty"`
+	PinnedMessage                      *Message         `json:"pinned_message,omitempty"`
+	Permissions                        *ChatPermissions `json:"permissions,omitempty"`
+	CanSendPaidMedia                   bool             `json:"can_send_paid_media,omitempty"`
+	SlowModeDelay                      int              `json:"slow_mode_delay,omitempty"`
+	UnrestrictBoostCount               int              `json:"unrestrict_boost_count,omitempty"`
+	MessageAutoDeleteTime              int              `json:"message_auto_delete_time,omitempty"`
+	HasAggressiveAntiSpamEnabled       bool             `json:"has_aggressive_anti_spam_enabled,omitempty"`
+	HasHiddenMembers                   bool             `json:"has_hidden_members,omitempty"`
+	HasProtectedContent                bool             `json:"has_protected_content,omitempty"`
+	StickerSetName                     string           `json:"sticker_set_name,omitempty"`
+	CanSetStickerSet                   bool             `json:"can_set_sticker_set,omitempty"`
+	CustomEmojiStickerSetName          string           `json:"custom_emoji_sticker_set_name,omitempty"`
+	LinkedChatId                       int64            `json:"linked_chat_id,omitempty"`
+	Location                           *ChatLocation    `json:"location,omitempty"`
+	AvailableReactions                 []ReactionType   `json:"available_reactions,omitempty"`
+	BackgroundCustomEmojiId            string           `json:"background_custom_emoji_id,omitempty"`
+	ProfileAccentColorId               int              `json:"profile_accent_color_id,omitempty"`
+	ProfileBackgroundCustomEmojiId     string           `json:"profile_background_custom_emoji_id,omitempty"`
+	EmojiStatusCustomEmojiId           string           `json:"emoji_status_custom_emoji_id,omitempty"`
+	EmojiStatusExpirationDate          int64            `json:"emoji_status_expiration_date,omitempty"`
+}
+
+type ChatLocation struct {
+	Location Location `json:"location"`
+	Address  string   `json:"address"`
 }
 
 type ChatPhoto struct {
@@ -276,3 +591,123 @@ type FileResponse struct {
 		FilePath string `json:"file_path"`
 	} `json:"result"`
 }
+
+type ErrorResponse struct {
+	Ok          bool                `json:"ok"`
+	ErrorCode   int                 `json:"error_code"`
+	Description string              `json:"description"`
+	Parameters  *ResponseParameters `json:"parameters,omitempty"`
+}
+
+type ResponseParameters struct {
+	MigrateToChatId int64 `json:"migrate_to_chat_id,omitempty"`
+	RetryAfter      int   `json:"retry_after,omitempty"`
+}
+
+type BoolResponse struct {
+	Ok     bool `json:"ok"`
+	Result bool `json:"result"`
+}
+
+type Location struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+type InlineQuery struct {
+	Id       string    `json:"id"`
+	From     From      `json:"from"`
+	Query    string    `json:"query"`
+	Offset   string    `json:"offset"`
+	ChatType string    `json:"chat_type,omitempty"`
+	Location *Location `json:"location,omitempty"`
+}
+
+type ChosenInlineResult struct {
+	ResultId        string    `json:"result_id"`
+	From            From      `json:"from"`
+	Location        *Location `json:"location,omitempty"`
+	InlineMessageId string    `json:"inline_message_id,omitempty"`
+	Query           string    `json:"query"`
+}
+
+type ChatJoinRequest struct {
+	Chat       Chat        `json:"chat"`
+	From       From        `json:"from"`
+	UserChatId int64       `json:"user_chat_id"`
+	Date       int         `json:"date"`
+	Bio        string      `json:"bio,omitempty"`
+	InviteLink *InviteLink `json:"invite_link,omitempty"`
+}
+
+type PollAnswer struct {
+	PollId    string `json:"poll_id"`
+	VoterChat *Chat  `json:"voter_chat,omitempty"`
+	User      *From  `json:"user,omitempty"`
+	OptionIds []int  `json:"option_ids"`
+}
+
+type ReactionType struct {
+	Type          string `json:"type"`
+	Emoji         string `json:"emoji,omitempty"`
+	CustomEmojiId string `json:"custom_emoji_id,omitempty"`
+}
+
+type ReactionCount struct {
+	Type       ReactionType `json:"type"`
+	TotalCount int          `json:"total_count"`
+}
+
+type MessageReactionUpdated struct {
+	Chat        Chat           `json:"chat"`
+	MessageId   int            `json:"message_id"`
+	User        *From          `json:"user,omitempty"`
+	ActorChat   *Chat          `json:"actor_chat,omitempty"`
+	Date        int            `json:"date"`
+	OldReaction []ReactionType `json:"old_reaction"`
+	NewReaction []ReactionType `json:"new_reaction"`
+}
+
+type MessageReactionCountUpdated struct {
+	Chat      Chat            `json:"chat"`
+	MessageId int             `json:"message_id"`
+	Date      int             `json:"date"`
+	Reactions []ReactionCount `json:"reactions"`
+}
+
+type ShippingAddress struct {
+	CountryCode string `json:"country_code"`
+	State       string `json:"state"`
+	City        string `json:"city"`
+	StreetLine1 string `json:"street_line1"`
+	StreetLine2 string `json:"street_line2"`
+	PostCode    string `json:"post_code"`
+}
+
+type OrderInfo struct {
+	Name            string           `json:"name,omitempty"`
+	PhoneNumber     string           `json:"phone_number,omitempty"`
+	Email           string           `json:"email,omitempty"`
+	ShippingAddress *ShippingAddress `json:"shipping_address,omitempty"`
+}
+
+type ShippingQuery struct {
+	Id              string          `json:"id"`
+	From            From            `json:"from"`
+	InvoicePayload  string          `json:"invoice_payload"`
+	ShippingAddress ShippingAddress `json:"shipping_address"`
+}
+
+type PreCheckoutQuery struct {
+	Id               string     `json:"id"`
+	From             From       `json:"from"`
+	Currency         string     `json:"currency"`
+	TotalAmount      int        `json:"total_amount"`
+	InvoicePayload   string     `json:"invoice_payload"`
+	ShippingOptionId string     `json:"shipping_option_id,omitempty"`
+	OrderInfo        *OrderInfo `json:"order_info,omitempty"`
+}
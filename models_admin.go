@@ -0,0 +1,138 @@
+package teledau
+
+// ChatPermissions are the actions non-administrator members may take. Permissions left false are denied.
+type ChatPermissions struct {
+	CanSendMessages       bool `json:"can_send_messages,omitempty" yaml:"can_send_messages,omitempty"`
+	CanSendAudios         bool `json:"can_send_audios,omitempty" yaml:"can_send_audios,omitempty"`
+	CanSendDocuments      bool `json:"can_send_documents,omitempty" yaml:"can_send_documents,omitempty"`
+	CanSendPhotos         bool `json:"can_send_photos,omitempty" yaml:"can_send_photos,omitempty"`
+	CanSendVideos         bool `json:"can_send_videos,omitempty" yaml:"can_send_videos,omitempty"`
+	CanSendVideoNotes     bool `json:"can_send_video_notes,omitempty" yaml:"can_send_video_notes,omitempty"`
+	CanSendVoiceNotes     bool `json:"can_send_voice_notes,omitempty" yaml:"can_send_voice_notes,omitempty"`
+	CanSendPolls          bool `json:"can_send_polls,omitempty" yaml:"can_send_polls,omitempty"`
+	CanSendOtherMessages  bool `json:"can_send_other_messages,omitempty" yaml:"can_send_other_messages,omitempty"`
+	CanAddWebPagePreviews bool `json:"can_add_web_page_previews,omitempty" yaml:"can_add_web_page_previews,omitempty"`
+	CanChangeInfo         bool `json:"can_change_info,omitempty" yaml:"can_change_info,omitempty"`
+	CanInviteUsers        bool `json:"can_invite_users,omitempty" yaml:"can_invite_users,omitempty"`
+	CanPinMessages        bool `json:"can_pin_messages,omitempty" yaml:"can_pin_messages,omitempty"`
+	CanManageTopics       bool `json:"can_manage_topics,omitempty" yaml:"can_manage_topics,omitempty"`
+}
+
+// ChatAdministratorRights are the rights of an administrator. Rights left false are not granted.
+type ChatAdministratorRights struct {
+	IsAnonymous         bool `json:"is_anonymous,omitempty" yaml:"is_anonymous,omitempty"`
+	CanManageChat       bool `json:"can_manage_chat,omitempty" yaml:"can_manage_chat,omitempty"`
+	CanDeleteMessages   bool `json:"can_delete_messages,omitempty" yaml:"can_delete_messages,omitempty"`
+	CanManageVideoChats bool `json:"can_manage_video_chats,omitempty" yaml:"can_manage_video_chats,omitempty"`
+	CanRestrictMembers  bool `json:"can_restrict_members,omitempty" yaml:"can_restrict_members,omitempty"`
+	CanPromoteMembers   bool `json:"can_promote_members,omitempty" yaml:"can_promote_members,omitempty"`
+	CanChangeInfo       bool `json:"can_change_info,omitempty" yaml:"can_change_info,omitempty"`
+	CanInviteUsers      bool `json:"can_invite_users,omitempty" yaml:"can_invite_users,omitempty"`
+	CanPostStories      bool `json:"can_post_stories,omitempty" yaml:"can_post_stories,omitempty"`
+	CanEditStories      bool `json:"can_edit_stories,omitempty" yaml:"can_edit_stories,omitempty"`
+	CanDeleteStories    bool `json:"can_delete_stories,omitempty" yaml:"can_delete_stories,omitempty"`
+	CanPostMessages     bool `json:"can_post_messages,omitempty" yaml:"can_post_messages,omitempty"`
+	CanEditMessages     bool `json:"can_edit_messages,omitempty" yaml:"can_edit_messages,omitempty"`
+	CanPinMessages      bool `json:"can_pin_messages,omitempty" yaml:"can_pin_messages,omitempty"`
+	CanManageTopics     bool `json:"can_manage_topics,omitempty" yaml:"can_manage_topics,omitempty"`
+}
+
+// BanChatMemberRequest bans a user. UntilDate is a unix time; 0 or a date less than
+// 30 seconds or more than 366 days away bans forever.
+type BanChatMemberRequest struct {
+	ChatId         string `json:"chat_id"`
+	UserId         int64  `json:"user_id"`
+	UntilDate      int64  `json:"until_date,omitempty"`
+	RevokeMessages bool   `json:"revoke_messages,omitempty"`
+}
+
+type RestrictChatMemberRequest struct {
+	ChatId                        string          `json:"chat_id"`
+	UserId                        int64           `json:"user_id"`
+	Permissions                   ChatPermissions `json:"permissions"`
+	UseIndependentChatPermissions bool            `json:"use_independent_chat_permissions,omitempty"`
+	UntilDate                     int64           `json:"until_date,omitempty"`
+}
+
+// PromoteChatMemberRequest grants the given rights; promoting with no rights demotes the user.
+type PromoteChatMemberRequest struct {
+	ChatId string `json:"chat_id"`
+	UserId int64  `json:"user_id"`
+	ChatAdministratorRights
+}
+
+type SetChatPermissionsRequest struct {
+	ChatId                        string          `json:"chat_id"`
+	Permissions                   ChatPermissions `json:"permissions"`
+	UseIndependentChatPermissions bool            `json:"use_independent_chat_permissions,omitempty"`
+}
+
+type ChatMemberResponse struct {
+	Ok     bool          `json:"ok"`
+	Result NewChatMember `json:"result"`
+}
+
+type ChatMembersResponse struct {
+	Ok     bool            `json:"ok"`
+	Result []NewChatMember `json:"result"`
+}
+
+type IntResponse struct {
+	Ok     bool `json:"ok"`
+	Result int  `json:"result"`
+}
+
+type chatMemberRequest struct {
+	ChatId       string `json:"chat_id"`
+	UserId       int64  `json:"user_id,omitempty"`
+	SenderChatId int64  `json:"sender_chat_id,omitempty"`
+	OnlyIfBanned bool   `json:"only_if_banned,omitempty"`
+	CustomTitle  string `json:"custom_title,omitempty"`
+}
+
+// IsAdministrator reports whether the member is the owner or an administrator of the chat.
+func (m NewChatMember) IsAdministrator() bool {
+	return m.Status == ChatMemberStatusCreator || m.Status == ChatMemberStatusAdministrator
+}
+
+// IsInChat reports whether the user is currently a member of the chat, including restricted members.
+func (m NewChatMember) IsInChat() bool {
+	switch m.Status {
+	case ChatMemberStatusCreator, ChatMemberStatusAdministrator, ChatMemberStatusMember:
+		return true
+	case ChatMemberStatusRestricted:
+		return m.IsMember
+	}
+
+	return false
+}
+
+// AdministratorRights returns the rights of an administrator. Other members have none;
+// the owner has all rights implicitly and only IsAnonymous is reported for it.
+func (m NewChatMember) AdministratorRights() ChatAdministratorRights {
+	if m.Status == ChatMemberStatusCreator {
+		return ChatAdministratorRights{IsAnonymous: m.IsAnonymous}
+	}
+
+	if m.Status != ChatMemberStatusAdministrator {
+		return ChatAdministratorRights{}
+	}
+
+	return ChatAdministratorRights{
+		IsAnonymous:         m.IsAnonymous,
+		CanManageChat:       m.CanManageChat,
+		CanDeleteMessages:   m.CanDeleteMessages,
+		CanManageVideoChats: m.CanManageVideoChats,
+		CanRestrictMembers:  m.CanRestrictMembers,
+		CanPromoteMembers:   m.CanPromoteMembers,
+		CanChangeInfo:       m.CanChangeInfo,
+		CanInviteUsers:      m.CanInviteUsers,
+		CanPostStories:      m.CanPostStories,
+		CanEditStories:      m.CanEditStories,
+		CanDeleteStories:    m.CanDeleteStories,
+		CanPostMessages:     m.CanPostMessages,
+		CanEditMessages:     m.CanEditMessages,
+		CanPinMessages:      m.CanPinMessages,
+		CanManageTopics:     m.CanManageTopics,
+	}
+}
@@ -0,0 +1,17 @@
+package teledau
+
+type chatRequest struct {
+	ChatId              string `json:"chat_id"`
+	MessageId           int    `json:"message_id,omitempty"`
+	DisableNotification bool   `json:"disable_notification,omitempty"`
+}
+
+type chatTitleRequest struct {
+	ChatId string `json:"chat_id"`
+	Title  string `json:"title"`
+}
+
+type chatDescriptionRequest struct {
+	ChatId      string `json:"chat_id"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,66 @@
+package teledau
+
+// EditMessageMediaRequest replaces the photo or video of a message, or of one item of an album.
+// The new media is Media, or Photo for a base64-encoded image. Type is one of the MediaType* constants
+// and defaults to a photo. The caption replaces the old one, so pass it again to keep it.
+//
+// Messages sent via inline mode are identified by InlineMessageId instead of ChatId and MessageId.
+type EditMessageMediaRequest struct {
+	ChatId          string          `json:"chat_id"`
+	MessageId       int             `json:"message_id"`
+	InlineMessageId string          `json:"inline_message_id,omitempty"`
+	Type            string          `json:"type,omitempty"`
+	Media           InputFile       `json:"-"`
+	Photo           string          `json:"photo"`
+	Caption         string          `json:"caption,omitempty"`
+	ParseMode       string          `json:"parse_mode,omitempty"`
+	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
+	ReplyMarkup     interface{}     `json:"reply_markup,omitempty"`
+
+	ShowCaptionAboveMedia bool `json:"show_caption_above_media,omitempty"`
+	HasSpoiler            bool `json:"has_spoiler,omitempty"`
+	// Width, Height, Duration and SupportsStreaming describe a video.
+	Width             int  `json:"width,omitempty"`
+	Height            int  `json:"height,omitempty"`
+	Duration          int  `json:"duration,omitempty"`
+	SupportsStreaming bool `json:"supports_streaming,omitempty"`
+}
+
+// inputMedia is the InputMediaPhoto or InputMediaVideo sent by EditMessageMedia.
+type inputMedia struct {
+	MediaGroup
+	ShowCaptionAboveMedia bool `json:"show_caption_above_media,omitempty"`
+	HasSpoiler            bool `json:"has_spoiler,omitempty"`
+	Width                 int  `json:"width,omitempty"`
+	Height                int  `json:"height,omitempty"`
+	Duration              int  `json:"duration,omitempty"`
+	SupportsStreaming     bool `json:"supports_streaming,omitempty"`
+}
+
+// EditMessageReplyMarkupRequest replaces the inline keyboard of a message; a nil ReplyMarkup removes it.
+type EditMessageReplyMarkupRequest struct {
+	ChatId          string      `json:"chat_id,omitempty"`
+	MessageId       int         `json:"message_id,omitempty"`
+	InlineMessageId string      `json:"inline_message_id,omitempty"`
+	ReplyMarkup     interface{} `json:"reply_markup,omitempty"`
+}
+
+// EditMessageLiveLocationRequest moves a live location. LivePeriod extends the period the location
+// can be updated for, in seconds from the time the message was sent; 0x7FFFFFFF makes it indefinite.
+type EditMessageLiveLocationRequest struct {
+	ChatId               string      `json:"chat_id,omitempty"`
+	MessageId            int         `json:"message_id,omitempty"`
+	InlineMessageId      string      `json:"inline_message_id,omitempty"`
+	Latitude             float64     `json:"latitude"`
+	Longitude            float64     `json:"longitude"`
+	LivePeriod           int         `json:"live_period,omitempty"`
+	HorizontalAccuracy   float64     `json:"horizontal_accuracy,omitempty"`
+	Heading              int         `json:"heading,omitempty"`
+	ProximityAlertRadius int         `json:"proximity_alert_radius,omitempty"`
+	ReplyMarkup          interface{} `json:"reply_markup,omitempty"`
+}
+
+type PollResultResponse struct {
+	Ok     bool `json:"ok"`
+	Result Poll `json:"result"`
+}
@@ -0,0 +1,52 @@
+package teledau
+
+type ForumTopic struct {
+	MessageThreadId   int    `json:"message_thread_id"`
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiId string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+type ForumTopicResponse struct {
+	Ok     bool       `json:"ok"`
+	Result ForumTopic `json:"result"`
+}
+
+type StickersResponse struct {
+	Ok     bool      `json:"ok"`
+	Result []Sticker `json:"result"`
+}
+
+// CreateForumTopicRequest creates a topic; IconColor is one of the ForumTopicIconColor* constants
+// and IconCustomEmojiId one of the stickers returned by GetForumTopicIconStickers.
+type CreateForumTopicRequest struct {
+	ChatId            string `json:"chat_id"`
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color,omitempty"`
+	IconCustomEmojiId string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// EditForumTopicRequest changes the name and/or the icon of a topic. Fields left nil are kept;
+// an empty IconCustomEmojiId removes the icon.
+type EditForumTopicRequest struct {
+	ChatId            string  `json:"chat_id"`
+	MessageThreadId   int     `json:"message_thread_id"`
+	Name              *string `json:"name,omitempty"`
+	IconCustomEmojiId *string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+type forumTopicRequest struct {
+	ChatId          string `json:"chat_id"`
+	MessageThreadId int    `json:"message_thread_id,omitempty"`
+	Name            string `json:"name,omitempty"`
+}
+
+// TopicId returns the forum topic the message belongs to, or 0 for the general topic
+// and for messages outside forums.
+func (m Message) TopicId() int {
+	if !m.IsTopicMessage {
+		return 0
+	}
+
+	return m.MessageThreadId
+}
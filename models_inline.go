@@ -0,0 +1,387 @@
+package teledau
+
+import "encoding/json"
+
+type AnswerInlineQueryRequest struct {
+	InlineQueryId string                    `json:"inline_query_id"`
+	Results       []InlineQueryResult       `json:"results"`
+	CacheTime     int                       `json:"cache_time,omitempty"`
+	IsPersonal    bool                      `json:"is_personal,omitempty"`
+	NextOffset    string                    `json:"next_offset,omitempty"`
+	Button        *InlineQueryResultsButton `json:"button,omitempty"`
+}
+
+type InlineQueryResultsButton struct {
+	Text           string      `json:"text"`
+	WebApp         *WebAppInfo `json:"web_app,omitempty"`
+	StartParameter string      `json:"start_parameter,omitempty"`
+}
+
+// InlineQueryResult is one of the InlineQueryResult* types below.
+// The "type" field is added when the result is marshalled.
+type InlineQueryResult interface {
+	inlineQueryResultType() string
+}
+
+// InputMessageContent is one of the Input*MessageContent types below.
+type InputMessageContent interface {
+	inputMessageContent()
+}
+
+type InlineQueryResultArticle struct {
+	Id                  string                `json:"id"`
+	Title               string                `json:"title"`
+	InputMessageContent InputMessageContent   `json:"input_message_content"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	URL                 string                `json:"url,omitempty"`
+	HideURL             bool                  `json:"hide_url,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+type InlineQueryResultPhoto struct {
+	Id                  string                `json:"id"`
+	PhotoURL            string                `json:"photo_url"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	PhotoWidth          int                   `json:"photo_width,omitempty"`
+	PhotoHeight         int                   `json:"photo_height,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultGif struct {
+	Id                  string                `json:"id"`
+	GifURL              string                `json:"gif_url"`
+	GifWidth            int                   `json:"gif_width,omitempty"`
+	GifHeight           int                   `json:"gif_height,omitempty"`
+	GifDuration         int                   `json:"gif_duration,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	ThumbnailMimeType   string                `json:"thumbnail_mime_type,omitempty"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultVideo struct {
+	Id                  string                `json:"id"`
+	VideoURL            string                `json:"video_url"`
+	MimeType            string                `json:"mime_type"`
+	ThumbnailURL        string                `json:"thumbnail_url"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	VideoWidth          int                   `json:"video_width,omitempty"`
+	VideoHeight         int                   `json:"video_height,omitempty"`
+	VideoDuration       int                   `json:"video_duration,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultDocument struct {
+	Id                  string                `json:"id"`
+	Title               string                `json:"title"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	DocumentURL         string                `json:"document_url"`
+	MimeType            string                `json:"mime_type"`
+	Description         string                `json:"description,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+	ThumbnailURL        string                `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth      int                   `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight     int                   `json:"thumbnail_height,omitempty"`
+}
+
+type InlineQueryResultCachedPhoto struct {
+	Id                  string                `json:"id"`
+	PhotoFileId         string                `json:"photo_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedGif struct {
+	Id                  string                `json:"id"`
+	GifFileId           string                `json:"gif_file_id"`
+	Title               string                `json:"title,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedVideo struct {
+	Id                  string                `json:"id"`
+	VideoFileId         string                `json:"video_file_id"`
+	Title               string                `json:"title"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedDocument struct {
+	Id                  string                `json:"id"`
+	Title               string                `json:"title"`
+	DocumentFileId      string                `json:"document_file_id"`
+	Description         string                `json:"description,omitempty"`
+	Caption             string                `json:"caption,omitempty"`
+	ParseMode           string                `json:"parse_mode,omitempty"`
+	CaptionEntities     []Entities            `json:"caption_entities,omitempty"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InlineQueryResultCachedSticker struct {
+	Id                  string                `json:"id"`
+	StickerFileId       string                `json:"sticker_file_id"`
+	ReplyMarkup         *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	InputMessageContent InputMessageContent   `json:"input_message_content,omitempty"`
+}
+
+type InputTextMessageContent struct {
+	MessageText           string     `json:"message_text"`
+	ParseMode             string     `json:"parse_mode,omitempty"`
+	Entities              []Entities `json:"entities,omitempty"`
+	DisableWebPagePreview bool       `json:"disable_web_page_preview,omitempty"`
+}
+
+type InputLocationMessageContent struct {
+	Latitude             float64 `json:"latitude"`
+	Longitude            float64 `json:"longitude"`
+	HorizontalAccuracy   float64 `json:"horizontal_accuracy,omitempty"`
+	LivePeriod           int     `json:"live_period,omitempty"`
+	Heading              int     `json:"heading,omitempty"`
+	ProximityAlertRadius int     `json:"proximity_alert_radius,omitempty"`
+}
+
+type InputVenueMessageContent struct {
+	Latitude        float64 `json:"latitude"`
+	Longitude       float64 `json:"longitude"`
+	Title           string  `json:"title"`
+	Address         string  `json:"address"`
+	FoursquareId    string  `json:"foursquare_id,omitempty"`
+	FoursquareType  string  `json:"foursquare_type,omitempty"`
+	GooglePlaceId   string  `json:"google_place_id,omitempty"`
+	GooglePlaceType string  `json:"google_place_type,omitempty"`
+}
+
+type InputContactMessageContent struct {
+	PhoneNumber string `json:"phone_number"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name,omitempty"`
+	Vcard       string `json:"vcard,omitempty"`
+}
+
+func (InputTextMessageContent) inputMessageContent()     {}
+func (InputLocationMessageContent) inputMessageContent() {}
+func (InputVenueMessageContent) inputMessageContent()    {}
+func (InputContactMessageContent) inputMessageContent()  {}
+
+func (InlineQueryResultArticle) inlineQueryResultType() string {
+	return InlineResultTypeArticle
+}
+
+func (InlineQueryResultPhoto) inlineQueryResultType() string {
+	return InlineResultTypePhoto
+}
+
+func (InlineQueryResultGif) inlineQueryResultType() string {
+	return InlineResultTypeGif
+}
+
+func (InlineQueryResultVideo) inlineQueryResultType() string {
+	return InlineResultTypeVideo
+}
+
+func (InlineQueryResultDocument) inlineQueryResultType() string {
+	return InlineResultTypeDocument
+}
+
+func (InlineQueryResultCachedPhoto) inlineQueryResultType() string {
+	return InlineResultTypePhoto
+}
+
+func (InlineQueryResultCachedGif) inlineQueryResultType() string {
+	return InlineResultTypeGif
+}
+
+func (InlineQueryResultCachedVideo) inlineQueryResultType() string {
+	return InlineResultTypeVideo
+}
+
+func (InlineQueryResultCachedDocument) inlineQueryResultType() string {
+	return InlineResultTypeDocument
+}
+
+func (InlineQueryResultCachedSticker) inlineQueryResultType() string {
+	return InlineResultTypeSticker
+}
+
+func (r InlineQueryResultArticle) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultArticle
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultPhoto) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultPhoto
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultGif) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultGif
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultVideo) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultVideo
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultDocument) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultDocument
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultCachedPhoto) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultCachedPhoto
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultCachedGif) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultCachedGif
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultCachedVideo) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultCachedVideo
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultCachedDocument) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultCachedDocument
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+func (r InlineQueryResultCachedSticker) MarshalJSON() ([]byte, error) {
+	type alias InlineQueryResultCachedSticker
+	return marshalInlineQueryResult(r, alias(r))
+}
+
+// marshalInlineQueryResult encodes fields (an alias of result without MarshalJSON)
+// with the result's "type" field prepended.
+func marshalInlineQueryResult(result InlineQueryResult, fields interface{}) ([]byte, error) {
+	fieldsData, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	typeData, err := json.Marshal(result.inlineQueryResultType())
+	if err != nil {
+		return nil, err
+	}
+
+	data := append([]byte(`{"type":`), typeData...)
+	if len(fieldsData) > 2 {
+		data = append(data, ',')
+	}
+
+	return append(data, fieldsData[1:]...), nil
+}
+
+// NewInlineQueryResultArticle returns an article result that sends text when chosen.
+func NewInlineQueryResultArticle(id, title, text, parseMode string) InlineQueryResultArticle {
+	return InlineQueryResultArticle{
+		Id:    id,
+		Title: title,
+		InputMessageContent: InputTextMessageContent{
+			MessageText: text,
+			ParseMode:   parseMode,
+		},
+	}
+}
+
+// NewInlineQueryResultPhoto returns a photo result for a photo available by URL.
+func NewInlineQueryResultPhoto(id, photoURL, thumbnailURL, caption string) InlineQueryResultPhoto {
+	return InlineQueryResultPhoto{
+		Id:           id,
+		PhotoURL:     photoURL,
+		ThumbnailURL: thumbnailURL,
+		Caption:      caption,
+	}
+}
+
+// NewInlineQueryResultGif returns a GIF result for an animation available by URL.
+func NewInlineQueryResultGif(id, gifURL, thumbnailURL string) InlineQueryResultGif {
+	return InlineQueryResultGif{
+		Id:           id,
+		GifURL:       gifURL,
+		ThumbnailURL: thumbnailURL,
+	}
+}
+
+// NewInlineQueryResultVideo returns a video result; mimeType is "text/html" or "video/mp4".
+func NewInlineQueryResultVideo(id, videoURL, mimeType, thumbnailURL, title string) InlineQueryResultVideo {
+	return InlineQueryResultVideo{
+		Id:           id,
+		VideoURL:     videoURL,
+		MimeType:     mimeType,
+		ThumbnailURL: thumbnailURL,
+		Title:        title,
+	}
+}
+
+// NewInlineQueryResultDocument returns a document result; mimeType is "application/pdf" or "application/zip".
+func NewInlineQueryResultDocument(id, title, documentURL, mimeType string) InlineQueryResultDocument {
+	return InlineQueryResultDocument{
+		Id:          id,
+		Title:       title,
+		DocumentURL: documentURL,
+		MimeType:    mimeType,
+	}
+}
+
+// NewInlineQueryResultCachedPhoto returns a photo result for a photo already stored on Telegram servers.
+func NewInlineQueryResultCachedPhoto(id, photoFileId string) InlineQueryResultCachedPhoto {
+	return InlineQueryResultCachedPhoto{Id: id, PhotoFileId: photoFileId}
+}
+
+// NewInlineQueryResultCachedGif returns a GIF result for an animation already stored on Telegram servers.
+func NewInlineQueryResultCachedGif(id, gifFileId string) InlineQueryResultCachedGif {
+	return InlineQueryResultCachedGif{Id: id, GifFileId: gifFileId}
+}
+
+// NewInlineQueryResultCachedVideo returns a video result for a video already stored on Telegram servers.
+func NewInlineQueryResultCachedVideo(id, videoFileId, title string) InlineQueryResultCachedVideo {
+	return InlineQueryResultCachedVideo{Id: id, VideoFileId: videoFileId, Title: title}
+}
+
+// NewInlineQueryResultCachedDocument returns a document result for a file already stored on Telegram servers.
+func NewInlineQueryResultCachedDocument(id, title, documentFileId string) InlineQueryResultCachedDocument {
+	return InlineQueryResultCachedDocument{Id: id, Title: title, DocumentFileId: documentFileId}
+}
+
+// NewInlineQueryResultCachedSticker returns a sticker result for a sticker already stored on Telegram servers.
+func NewInlineQueryResultCachedSticker(id, stickerFileId string) InlineQueryResultCachedSticker {
+	return InlineQueryResultCachedSticker{Id: id, StickerFileId: stickerFileId}
+}
@@ -0,0 +1,21 @@
+package teledau
+
+type StringResponse struct {
+	Ok     bool   `json:"ok"`
+	Result string `json:"result"`
+}
+
+type inviteLinkRequest struct {
+	ChatId     string `json:"chat_id"`
+	InviteLink string `json:"invite_link,omitempty"`
+	Name       string `json:"name,omitempty"`
+}
+
+// JoinedViaInviteLink returns the invite link used when a chat_member update reports a user joining the chat.
+func (m ChatMember) JoinedViaInviteLink() (string, bool) {
+	if len(m.InviteLink.InviteLink) == 0 || m.OldChatMember.IsInChat() || !m.NewChatMember.IsInChat() {
+		return "", false
+	}
+
+	return m.InviteLink.InviteLink, true
+}
@@ -0,0 +1,63 @@
+package teledau
+
+type ForwardMessageRequest struct {
+	ChatId              string `json:"chat_id"`
+	MessageThreadId     int    `json:"message_thread_id,omitempty"`
+	FromChatId          string `json:"from_chat_id"`
+	MessageId           int    `json:"message_id"`
+	DisableNotification bool   `json:"disable_notification,omitempty"`
+	ProtectContent      bool   `json:"protect_content,omitempty"`
+}
+
+// ForwardMessagesRequest forwards several messages at once, keeping albums grouped.
+type ForwardMessagesRequest struct {
+	ChatId              string `json:"chat_id"`
+	MessageThreadId     int    `json:"message_thread_id,omitempty"`
+	FromChatId          string `json:"from_chat_id"`
+	MessageIds          []int  `json:"message_ids"`
+	DisableNotification bool   `json:"disable_notification,omitempty"`
+	ProtectContent      bool   `json:"protect_content,omitempty"`
+}
+
+// CopyMessageRequest copies a message without a link to the original. A nil Caption keeps the caption
+// of the original message; an empty one removes it.
+type CopyMessageRequest struct {
+	ChatId                string          `json:"chat_id"`
+	MessageThreadId       int             `json:"message_thread_id,omitempty"`
+	FromChatId            string          `json:"from_chat_id"`
+	MessageId             int             `json:"message_id"`
+	Caption               *string         `json:"caption,omitempty"`
+	ParseMode             string          `json:"parse_mode,omitempty"`
+	CaptionEntities       []MessageEntity `json:"caption_entities,omitempty"`
+	ShowCaptionAboveMedia bool            `json:"show_caption_above_media,omitempty"`
+
+	DisableNotification bool             `json:"disable_notification,omitempty"`
+	ProtectContent      bool             `json:"protect_content,omitempty"`
+	ReplyParameters     *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyMarkup         interface{}      `json:"reply_markup,omitempty"`
+}
+
+type CopyMessagesRequest struct {
+	ChatId              string `json:"chat_id"`
+	MessageThreadId     int    `json:"message_thread_id,omitempty"`
+	FromChatId          string `json:"from_chat_id"`
+	MessageIds          []int  `json:"message_ids"`
+	DisableNotification bool   `json:"disable_notification,omitempty"`
+	ProtectContent      bool   `json:"protect_content,omitempty"`
+	RemoveCaption       bool   `json:"remove_caption,omitempty"`
+}
+
+type MessageIdResponse struct {
+	Ok     bool      `json:"ok"`
+	Result MessageId `json:"result"`
+}
+
+type MessageIdsResponse struct {
+	Ok     bool        `json:"ok"`
+	Result []MessageId `json:"result"`
+}
+
+type deleteMessagesRequest struct {
+	ChatId     string `json:"chat_id"`
+	MessageIds []int  `json:"message_ids"`
+}
@@ -1,30 +1,105 @@
 package teledau
 
 type MessageRequest struct {
-	ChatId                string      `json:"chat_id,omitempty"`
-	Text                  string      `json:"text,omitempty"`
-	ParseMode             string      `json:"parse_mode,omitempty"`
-	DisableWebPagePreview bool        `json:"disable_web_page_preview,omitempty"`
-	ReplyMarkup           interface{} `json:"reply_markup,omitempty"`
-	Photo                 []Photo     `json:"photo,omitempty"`
+	ChatId          string `json:"chat_id,omitempty"`
+	MessageThreadId int    `json:"message_thread_id,omitempty"`
+	Text            string `json:"text,omitempty"`
+	ParseMode       string `json:"parse_mode,omitempty"`
+	// Deprecated: use LinkPreviewOptions; SendMessage converts it to LinkPreviewOptions.IsDisabled.
+	DisableWebPagePreview bool                `json:"disable_web_page_preview,omitempty"`
+	LinkPreviewOptions    *LinkPreviewOptions `json:"link_preview_options,omitempty"`
+	DisableNotification   bool                `json:"disable_notification,omitempty"`
+	ProtectContent        bool                `json:"protect_content,omitempty"`
+	MessageEffectId       string              `json:"message_effect_id,omitempty"`
+	ReplyParameters       *ReplyParameters    `json:"reply_parameters,omitempty"`
+	ReplyMarkup           interface{}         `json:"reply_markup,omitempty"`
+	Photo                 []Photo             `json:"photo,omitempty"`
+	Entities              []MessageEntity     `json:"entities,omitempty"`
 }
 type EditMessageRequest struct {
-	MessageId             int         `json:"message_id,omitempty"`
-	ChatId                string      `json:"chat_id,omitempty"`
-	Text                  string      `json:"text,omitempty"`
-	ParseMode             string      `json:"parse_mode,omitempty"`
-	DisableWebPagePreview bool        `json:"disable_web_page_preview,omitempty"`
-	ReplyMarkup           interface{} `json:"reply_markup,omitempty"`
-	Photo                 []Photo     `json:"photo,omitempty"`
+	MessageId             int             `json:"message_id,omitempty"`
+	ChatId                string          `json:"chat_id,omitempty"`
+	InlineMessageId       string          `json:"inline_message_id,omitempty"`
+	Text                  string          `json:"text,omitempty"`
+	ParseMode             string          `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool            `json:"disable_web_page_preview,omitempty"`
+	ReplyMarkup           interface{}     `json:"reply_markup,omitempty"`
+	Photo                 []Photo         `json:"photo,omitempty"`
+	Entities              []MessageEntity `json:"entities,omitempty"`
 }
 type EditCaptionRequest struct {
-	MessageId             int         `json:"message_id,omitempty"`
-	ChatId                string      `json:"chat_id,omitempty"`
-	Text                  string      `json:"caption,omitempty"`
-	ParseMode             string      `json:"parse_mode,omitempty"`
-	DisableWebPagePreview bool        `json:"disable_web_page_preview,omitempty"`
-	ReplyMarkup           interface{} `json:"reply_markup,omitempty"`
-	Photo                 []Photo     `json:"photo,omitempty"`
+	MessageId             int             `json:"message_id,omitempty"`
+	ChatId                string          `json:"chat_id,omitempty"`
+	InlineMessageId       string          `json:"inline_message_id,omitempty"`
+	Text                  string          `json:"caption,omitempty"`
+	ParseMode             string          `json:"parse_mode,omitempty"`
+	DisableWebPagePreview bool            `json:"disable_web_page_preview,omitempty"`
+	ReplyMarkup           interface{}     `json:"reply_markup,omitempty"`
+	Photo                 []Photo         `json:"photo,omitempty"`
+	CaptionEntities       []MessageEntity `json:"caption_entities,omitempty"`
+}
+
+// PhotoRequest is sent as multipart/form-data; Photo is the base64-encoded image.
+type PhotoRequest struct {
+	ChatId              string           `json:"chat_id"`
+	MessageThreadId     int              `json:"message_thread_id,omitempty"`
+	Photo               string           `json:"photo"`
+	Caption             string           `json:"caption,omitempty"`
+	ParseMode           string           `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity  `json:"caption_entities,omitempty"`
+	DisableNotification bool             `json:"disable_notification,omitempty"`
+	ProtectContent      bool             `json:"protect_content,omitempty"`
+	MessageEffectId     string           `json:"message_effect_id,omitempty"`
+	ReplyParameters     *ReplyParameters `json:"reply_parameters,omitempty"`
+}
+
+// MediaGroupRequest is sent as multipart/form-data; Photos are base64-encoded images.
+type MediaGroupRequest struct {
+	ChatId              string           `json:"chat_id"`
+	MessageThreadId     int              `json:"message_thread_id,omitempty"`
+	Photos              []string         `json:"photos"`
+	Caption             string           `json:"caption,omitempty"`
+	ParseMode           string           `json:"parse_mode,omitempty"`
+	CaptionEntities     []MessageEntity  `json:"caption_entities,omitempty"`
+	DisableNotification bool             `json:"disable_notification,omitempty"`
+	ProtectContent      bool             `json:"protect_content,omitempty"`
+	MessageEffectId     string           `json:"message_effect_id,omitempty"`
+	ReplyParameters     *ReplyParameters `json:"reply_parameters,omitempty"`
+}
+
+// StickerRequest is sent as multipart/form-data; Sticker is the base64-encoded WEBP image.
+// StickerFile is sent instead when set, e.g. to resend a sticker by its file_id.
+type StickerRequest struct {
+	ChatId              string           `json:"chat_id"`
+	MessageThreadId     int              `json:"message_thread_id,omitempty"`
+	Sticker             string           `json:"sticker"`
+	StickerFile         InputFile        `json:"-"`
+	Emoji               string           `json:"emoji,omitempty"`
+	DisableNotification bool             `json:"disable_notification,omitempty"`
+	ProtectContent      bool             `json:"protect_content,omitempty"`
+	MessageEffectId     string           `json:"message_effect_id,omitempty"`
+	ReplyParameters     *ReplyParameters `json:"reply_parameters,omitempty"`
+	ReplyMarkup         interface{}      `json:"reply_markup,omitempty"`
+}
+
+// ReplyParameters describes the message to reply to. ChatId is only needed to reply
+// to a message in another chat; Quote must be an exact substring of the replied message.
+type ReplyParameters struct {
+	MessageId                int             `json:"message_id"`
+	ChatId                   string          `json:"chat_id,omitempty"`
+	AllowSendingWithoutReply bool            `json:"allow_sending_without_reply,omitempty"`
+	Quote                    string          `json:"quote,omitempty"`
+	QuoteParseMode           string          `json:"quote_parse_mode,omitempty"`
+	QuoteEntities            []MessageEntity `json:"quote_entities,omitempty"`
+	QuotePosition            int             `json:"quote_position,omitempty"`
+}
+
+type LinkPreviewOptions struct {
+	IsDisabled       bool   `json:"is_disabled,omitempty"`
+	URL              string `json:"url,omitempty"`
+	PreferSmallMedia bool   `json:"prefer_small_media,omitempty"`
+	PreferLargeMedia bool   `json:"prefer_large_media,omitempty"`
+	ShowAboveText    bool   `json:"show_above_text,omitempty"`
 }
 
 type InlineKeyboardMarkup struct {
@@ -64,6 +139,27 @@ type ForceReply struct {
 }
 
 type CreateChatInviteLinkRequest struct {
-	ChatID string `json:"chat_id"` // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
-	Name   string `json:"name"`    // Title of the invite link
+	ChatID             string `json:"chat_id"`                        // Unique identifier for the target chat or username of the target channel (in the format @channelusername)
+	Name               string `json:"name"`                           // Title of the invite link
+	ExpireDate         int64  `json:"expire_date,omitempty"`          // Unix time when the link expires
+	MemberLimit        int    `json:"member_limit,omitempty"`         // Maximum number of users joining via the link, 1-99999
+	CreatesJoinRequest bool   `json:"creates_join_request,omitempty"` // Users need an administrator's approval; can't be combined with MemberLimit
+}
+
+type EditChatInviteLinkRequest struct {
+	ChatID             string `json:"chat_id"`
+	InviteLink         string `json:"invite_link"`
+	Name               string `json:"name,omitempty"`
+	ExpireDate         int64  `json:"expire_date,omitempty"`
+	MemberLimit        int    `json:"member_limit,omitempty"`
+	CreatesJoinRequest bool   `json:"creates_join_request,omitempty"`
+}
+
+// CreateChatSubscriptionInviteLinkRequest creates a link for a paid channel subscription.
+// SubscriptionPeriod must be SubscriptionPeriod30Days; SubscriptionPrice is in Telegram Stars.
+type CreateChatSubscriptionInviteLinkRequest struct {
+	ChatID             string `json:"chat_id"`
+	Name               string `json:"name,omitempty"`
+	SubscriptionPeriod int    `json:"subscription_period"`
+	SubscriptionPrice  int    `json:"subscription_price"`
 }
@@ -0,0 +1,89 @@
+package teledau
+
+// StickerSet is a sticker set; StickerType is one of the StickerType* constants.
+type StickerSet struct {
+	Name        string     `json:"name"`
+	Title       string     `json:"title"`
+	StickerType string     `json:"sticker_type"`
+	Stickers    []Sticker  `json:"stickers"`
+	Thumbnail   *Thumbnail `json:"thumbnail,omitempty"`
+}
+
+type StickerSetResponse struct {
+	Ok     bool       `json:"ok"`
+	Result StickerSet `json:"result"`
+}
+
+// File is a file ready to be downloaded with DownloadByte.
+type File struct {
+	FileId       string `json:"file_id"`
+	FileUniqueId string `json:"file_unique_id"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	FilePath     string `json:"file_path,omitempty"`
+}
+
+type UploadedFileResponse struct {
+	Ok     bool `json:"ok"`
+	Result File `json:"result"`
+}
+
+// MaskPosition places a mask sticker on a face. Point is one of "forehead", "eyes", "mouth" or "chin".
+type MaskPosition struct {
+	Point  string  `json:"point"`
+	XShift float64 `json:"x_shift"`
+	YShift float64 `json:"y_shift"`
+	Scale  float64 `json:"scale"`
+}
+
+// InputSticker is a sticker to add to a set. Sticker is a file to upload or the file_id of
+// a file uploaded with UploadStickerFile; Format is one of the StickerFormat* constants.
+type InputSticker struct {
+	Sticker      InputFile
+	Format       string
+	EmojiList    []string
+	MaskPosition *MaskPosition
+	// Keywords help to find regular and custom emoji stickers in search.
+	Keywords []string
+}
+
+// CreateNewStickerSetRequest creates a set owned by UserId. Name must end with "_by_<bot username>".
+type CreateNewStickerSetRequest struct {
+	UserId          int64
+	Name            string
+	Title           string
+	Stickers        []InputSticker
+	StickerType     string
+	NeedsRepainting bool
+}
+
+// inputSticker is the JSON form of InputSticker, referring to its file by file_id or attach://.
+type inputSticker struct {
+	Sticker      string        `json:"sticker"`
+	Format       string        `json:"format"`
+	EmojiList    []string      `json:"emoji_list"`
+	MaskPosition *MaskPosition `json:"mask_position,omitempty"`
+	Keywords     []string      `json:"keywords,omitempty"`
+}
+
+type stickerRequest struct {
+	Sticker string `json:"sticker"`
+}
+
+type stickerPositionRequest struct {
+	Sticker  string `json:"sticker"`
+	Position int    `json:"position"`
+}
+
+type stickerEmojiListRequest struct {
+	Sticker   string   `json:"sticker"`
+	EmojiList []string `json:"emoji_list"`
+}
+
+type stickerSetRequest struct {
+	Name          string `json:"name"`
+	CustomEmojiId string `json:"custom_emoji_id,omitempty"`
+}
+
+type customEmojiStickersRequest struct {
+	CustomEmojiIds []string `json:"custom_emoji_ids"`
+}
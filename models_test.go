@@ -0,0 +1,63 @@
+package teledau
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMessage_Unmarshal(t *testing.T) {
+	data := []byte(`{
+		"message_id": 10,
+		"message_thread_id": 3,
+		"is_topic_message": true,
+		"from": {"id": 1, "is_bot": false, "first_name": "Daulet"},
+		"chat": {"id": -100123, "title": "Goal", "type": "supergroup", "is_forum": true},
+		"date": 1718000000,
+		"edit_date": 1718000100,
+		"media_group_id": "g1",
+		"via_bot": {"id": 2, "is_bot": true, "first_name": "bot"},
+		"forward_origin": {"type": "channel", "date": 1717000000, "chat": {"id": -100999, "type": "channel"}, "message_id": 77},
+		"caption": "Goal!",
+		"caption_entities": [{"type": "text_link", "offset": 0, "length": 4, "url": "https://example.com"}],
+		"document": {"file_id": "doc", "file_unique_id": "u", "file_name": "table.pdf"},
+		"sticker": {"file_id": "st", "file_unique_id": "su", "width": 512, "height": 512},
+		"poll": {"id": "p1", "question": "Who wins?", "options": [{"text": "A", "voter_count": 1}]},
+		"new_chat_members": [{"id": 5, "first_name": "New"}],
+		"pinned_message": {"message_id": 9, "chat": {"id": -100123, "type": "supergroup"}, "date": 1, "text": "pinned"},
+		"migrate_to_chat_id": -100456,
+		"reply_markup": {"inline_keyboard": [[{"text": "Open", "url": "https://example.com"}]]}
+	}`)
+
+	var message Message
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatal(err)
+	}
+
+	if !message.IsTopicMessage || message.MessageThreadId != 3 || !message.Chat.IsForum {
+		t.Errorf("topic fields not decoded: %+v", message)
+	}
+
+	if message.ForwardOrigin == nil || message.ForwardOrigin.Chat == nil || message.ForwardOrigin.MessageId != 77 {
+		t.Errorf("forward_origin not decoded: %+v", message.ForwardOrigin)
+	}
+
+	if len(message.CaptionEntities) != 1 || message.CaptionEntities[0].URL != "https://example.com" {
+		t.Errorf("caption_entities not decoded: %+v", message.CaptionEntities)
+	}
+
+	if message.Document == nil || message.Document.FileName != "table.pdf" {
+		t.Errorf("document not decoded: %+v", message.Document)
+	}
+
+	if message.Sticker == nil || message.Poll == nil || message.ViaBot == nil {
+		t.Errorf("sticker, poll or via_bot not decoded")
+	}
+
+	if message.PinnedMessage == nil || message.PinnedMessage.Text != "pinned" {
+		t.Errorf("pinned_message not decoded: %+v", message.PinnedMessage)
+	}
+
+	if len(message.NewChatMembers) != 1 || message.MigrateToChatId != -100456 || message.ReplyMarkup == nil {
+		t.Errorf("service fields not decoded: %+v", message)
+	}
+}
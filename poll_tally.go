@@ -0,0 +1,189 @@
+package teledau
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TrackedPoll is a poll sent by the bot together with the message it was sent in.
+// Poll is refreshed by every poll update, so it holds the latest counts and state.
+type TrackedPoll struct {
+	Poll      Poll      `json:"poll"`
+	ChatId    int64     `json:"chat_id"`
+	MessageId int       `json:"message_id"`
+	SentAt    time.Time `json:"sent_at"`
+}
+
+// PollVote is the current answer of one voter. UserId is the id of the chat for votes
+// cast on behalf of an anonymous chat administrator.
+type PollVote struct {
+	UserId    int64     `json:"user_id"`
+	Username  string    `json:"username,omitempty"`
+	FirstName string    `json:"first_name,omitempty"`
+	OptionIds []int     `json:"option_ids"`
+	VotedAt   time.Time `json:"voted_at"`
+}
+
+// PollResults are the votes recorded for a poll. Counts holds the number of votes per option.
+type PollResults struct {
+	Poll   TrackedPoll `json:"poll"`
+	Counts []int       `json:"counts"`
+	Votes  []PollVote  `json:"votes"`
+}
+
+// PollTally records who voted for what in polls sent by the bot, e.g. for match predictions.
+// The Bot API only reports voters of non-anonymous polls, in poll_answer updates; a voter that
+// retracts a vote is removed.
+//
+// Track every sent poll and feed it poll and poll_answer updates.
+type PollTally struct {
+	Storage Storage
+	// Prefix is prepended to every storage key.
+	Prefix string
+}
+
+func NewPollTally(storage Storage) *PollTally {
+	return &PollTally{Storage: storage, Prefix: "poll_tally:"}
+}
+
+// Track records a poll message returned by SendPoll.
+func (t *PollTally) Track(message Message) error {
+	if message.Poll == nil {
+		return fmt.Errorf("message %d has no poll", message.MessageId)
+	}
+
+	return t.savePoll(TrackedPoll{
+		Poll:      *message.Poll,
+		ChatId:    int64(message.Chat.Id),
+		MessageId: message.MessageId,
+		SentAt:    time.Unix(int64(message.Date), 0).UTC(),
+	})
+}
+
+// HandleUpdate records poll and poll_answer updates and ignores others.
+func (t *PollTally) HandleUpdate(u Update) error {
+	switch {
+	case u.Poll != nil:
+		tracked, err := t.TrackedPoll(u.Poll.Id)
+		if errors.Is(err, ErrStorageNotFound) {
+			tracked = &TrackedPoll{}
+		} else if err != nil {
+			return err
+		}
+
+		tracked.Poll = *u.Poll
+
+		return t.savePoll(*tracked)
+	case u.PollAnswer != nil:
+		return t.RecordAnswer(*u.PollAnswer, time.Now())
+	}
+
+	return nil
+}
+
+// RecordAnswer stores the answer of a voter, replacing an earlier one. An answer without
+// options retracts the vote.
+func (t *PollTally) RecordAnswer(answer PollAnswer, at time.Time) error {
+	vote := PollVote{OptionIds: answer.OptionIds, VotedAt: at.UTC()}
+	switch {
+	case answer.User != nil:
+		vote.UserId, vote.Username, vote.FirstName = answer.User.Id, answer.User.Username, answer.User.FirstName
+	case answer.VoterChat != nil:
+		vote.UserId, vote.Username, vote.FirstName = int64(answer.VoterChat.Id), answer.VoterChat.Username, answer.VoterChat.Title
+	default:
+		return fmt.Errorf("poll %s: answer without voter", answer.PollId)
+	}
+
+	return UpdateStorage(t.Storage, t.Prefix+"votes:"+answer.PollId, 0, func(current []byte) ([]byte, error) {
+		var votes []PollVote
+		if current != nil {
+			if err := json.Unmarshal(current, &votes); err != nil {
+				return nil, err
+			}
+		}
+
+		kept := votes[:0]
+		for _, v := range votes {
+			if v.UserId != vote.UserId {
+				kept = append(kept, v)
+			}
+		}
+
+		if len(vote.OptionIds) > 0 {
+			kept = append(kept, vote)
+		}
+
+		return json.Marshal(kept)
+	})
+}
+
+// TrackedPoll returns a recorded poll, or ErrStorageNotFound for an unknown one.
+func (t *PollTally) TrackedPoll(pollId string) (*TrackedPoll, error) {
+	data, err := t.Storage.Get(t.Prefix + "poll:" + pollId)
+	if err != nil {
+		return nil, err
+	}
+
+	var tracked TrackedPoll
+	if err := json.Unmarshal(data, &tracked); err != nil {
+		return nil, err
+	}
+
+	return &tracked, nil
+}
+
+// Votes returns the current votes of a poll, earliest first.
+func (t *PollTally) Votes(pollId string) ([]PollVote, error) {
+	data, err := t.Storage.Get(t.Prefix + "votes:" + pollId)
+	if errors.Is(err, ErrStorageNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var votes []PollVote
+	if err := json.Unmarshal(data, &votes); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(votes, func(i, j int) bool { return votes[i].VotedAt.Before(votes[j].VotedAt) })
+
+	return votes, nil
+}
+
+// Results returns a tracked poll with its votes counted per option.
+func (t *PollTally) Results(pollId string) (*PollResults, error) {
+	tracked, err := t.TrackedPoll(pollId)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := t.Votes(pollId)
+	if err != nil {
+		return nil, err
+	}
+
+	results := &PollResults{Poll: *tracked, Counts: make([]int, len(tracked.Poll.Options)), Votes: votes}
+	for _, vote := range votes {
+		for _, id := range vote.OptionIds {
+			if id >= 0 && id < len(results.Counts) {
+				results.Counts[id]++
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (t *PollTally) savePoll(tracked TrackedPoll) error {
+	data, err := json.Marshal(tracked)
+	if err != nil {
+		return err
+	}
+
+	return t.Storage.Set(t.Prefix+"poll:"+tracked.Poll.Id, data, 0)
+}
@@ -0,0 +1,88 @@
+package teledau
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPollRequest_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(PollRequest{ChatId: "@club", Question: "Who wins?", Options: []string{"Home", "Away"}, Type: PollTypeRegular})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var regular map[string]interface{}
+	if err := json.Unmarshal(data, &regular); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := regular["correct_option_id"]; ok {
+		t.Errorf("correct_option_id should be omitted for regular polls: %s", data)
+	}
+
+	options, _ := regular["options"].([]interface{})
+	if len(options) != 2 || options[0].(map[string]interface{})["text"] != "Home" {
+		t.Errorf("options should be sent as InputPollOption: %s", data)
+	}
+
+	data, err = json.Marshal(PollRequest{ChatId: "@club", Question: "Who wins?", Options: []string{"Home", "Away"}, Type: PollTypeQuiz})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var quiz map[string]interface{}
+	if err := json.Unmarshal(data, &quiz); err != nil {
+		t.Fatal(err)
+	}
+
+	if quiz["correct_option_id"] != float64(0) {
+		t.Errorf("correct_option_id 0 should be sent for quizzes: %s", data)
+	}
+}
+
+func TestPollTally(t *testing.T) {
+	tally := NewPollTally(NewMemoryStorage())
+	sent := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	poll := Poll{Id: "p1", Question: "Who wins?", Options: []Options{{Text: "Home"}, {Text: "Draw"}, {Text: "Away"}}, Type: PollTypeQuiz, CorrectOptionId: 2}
+	if err := tally.Track(Message{MessageId: 9, Chat: Chat{Id: -100}, Date: int(sent.Unix()), Poll: &poll}); err != nil {
+		t.Fatal(err)
+	}
+
+	answers := []PollAnswer{
+		{PollId: "p1", User: &From{Id: 1, Username: "ann"}, OptionIds: []int{0}},
+		{PollId: "p1", User: &From{Id: 2, Username: "bob"}, OptionIds: []int{2}},
+		{PollId: "p1", User: &From{Id: 1, Username: "ann"}, OptionIds: []int{2}},
+		{PollId: "p1", User: &From{Id: 3, Username: "cat"}, OptionIds: []int{1}},
+		{PollId: "p1", User: &From{Id: 3, Username: "cat"}},
+	}
+	for i, answer := range answers {
+		if err := tally.RecordAnswer(answer, sent.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closed := poll
+	closed.IsClosed = true
+	if err := tally.HandleUpdate(Update{Poll: &closed}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := tally.Results("p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !results.Poll.Poll.IsClosed || results.Poll.ChatId != -100 || results.Poll.MessageId != 9 || !results.Poll.SentAt.Equal(sent) {
+		t.Errorf("poll update should keep the tracked message: %+v", results.Poll)
+	}
+
+	if results.Counts[0] != 0 || results.Counts[1] != 0 || results.Counts[2] != 2 {
+		t.Errorf("Counts = %v, want [0 0 2]", results.Counts)
+	}
+
+	if len(results.Votes) != 2 || results.Votes[0].Username != "bob" || !results.Votes[1].VotedAt.Equal(sent.Add(2*time.Minute)) {
+		t.Errorf("unexpected votes: %+v", results.Votes)
+	}
+}
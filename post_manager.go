@@ -0,0 +1,282 @@
+package teledau
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrPostLayoutChanged is returned by PostManager.Update when the new version of a post
+// needs different messages than the published one, e.g. a text post that became an album.
+var ErrPostLayoutChanged = errors.New("post layout changed")
+
+// PublishedPost maps a post to the messages it was published as.
+type PublishedPost struct {
+	PostId   int64  `json:"post_id"`
+	ChatId   string `json:"chat_id"`
+	PostType int64  `json:"post_type"`
+	// MessageIds lists the photo messages first, followed by the text messages a long text was split into.
+	MessageIds []int64 `json:"message_ids"`
+	// ImageHashes are SHA-256 sums of the published images, so unchanged photos are not uploaded again.
+	ImageHashes []string `json:"image_hashes,omitempty"`
+	// Deleted is set once the messages are deleted, so deleting the post again does nothing.
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// PostManager edits and deletes published posts. It keeps the messages of every post in Storage;
+// posts published by PostScheduler carry their message ids and are tracked on first use.
+//
+// Update and Delete are idempotent: repeating them, or updating a post with unchanged content,
+// is not an error.
+type PostManager struct {
+	Client  Telegram
+	Storage Storage
+	// Prefix is prepended to every storage key.
+	Prefix string
+}
+
+func NewPostManager(client Telegram, storage Storage) *PostManager {
+	return &PostManager{Client: client, Storage: storage, Prefix: "post_manager:"}
+}
+
+// Track records the messages of a published post.
+func (m *PostManager) Track(post PostRequest) error {
+	if post.Status != PostStatusPublished || len(post.MessageIds) == 0 && post.MessageId == 0 {
+		return fmt.Errorf("post %d is not published", post.Id)
+	}
+
+	published := PublishedPost{
+		PostId:     post.Id,
+		ChatId:     post.ChatId(),
+		PostType:   resolvePostType(post),
+		MessageIds: post.MessageIds,
+	}
+
+	if len(published.MessageIds) == 0 {
+		published.MessageIds = []int64{post.MessageId}
+	}
+
+	for _, img := range post.Img {
+		published.ImageHashes = append(published.ImageHashes, imageHash(img))
+	}
+
+	return m.save(published)
+}
+
+// Published returns the messages of a post, or ErrPostNotFound if it is not tracked or was deleted.
+func (m *PostManager) Published(postId int64) (*PublishedPost, error) {
+	published, err := m.load(postId)
+	if err == nil && published.Deleted {
+		return nil, ErrPostNotFound
+	}
+
+	return published, err
+}
+
+func (m *PostManager) load(postId int64) (*PublishedPost, error) {
+	data, err := m.Storage.Get(m.key(postId))
+	if errors.Is(err, ErrStorageNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var published PublishedPost
+	if err := json.Unmarshal(data, &published); err != nil {
+		return nil, err
+	}
+
+	return &published, nil
+}
+
+// Update edits the published messages of post to its current text and images. Text posts are edited
+// with EditMessage, captions with EditCaption and changed photos are replaced with EditMessageMedia.
+// The post type and the number of images and text parts must stay the same, otherwise
+// ErrPostLayoutChanged is returned and the post has to be deleted and published again.
+func (m *PostManager) Update(post PostRequest) error {
+	published, err := m.lookup(post)
+	if err != nil {
+		return err
+	}
+
+	if published.Deleted {
+		return fmt.Errorf("post %d: %w", post.Id, ErrPostNotFound)
+	}
+
+	// Posts tracked from their first message id alone don't know the other photos of an album.
+	if resolvePostType(post) != published.PostType || len(post.Img) != len(published.ImageHashes) ||
+		len(published.MessageIds) < len(post.Img) {
+		return ErrPostLayoutChanged
+	}
+
+	chatId := published.ChatId
+	photos := published.MessageIds[:len(post.Img)]
+	texts := published.MessageIds[len(post.Img):]
+
+	var caption TextPart
+	var parts []TextPart
+	if len(post.Img) == 0 {
+		parts, err = postTextParts(post.Text, post.ParseMode, MaxMessageLength)
+	} else {
+		caption, parts, err = postCaptionParts(post.Text, post.ParseMode)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(parts) != len(texts) {
+		return ErrPostLayoutChanged
+	}
+
+	for i, img := range post.Img {
+		hash := imageHash(img)
+		if hash == published.ImageHashes[i] {
+			if i == 0 {
+				_, err = m.Client.EditCaption(EditCaptionRequest{
+					ChatId:          chatId,
+					MessageId:       int(photos[0]),
+					Text:            caption.Text,
+					CaptionEntities: caption.Entities,
+				})
+			}
+		} else {
+			edit := EditMessageMediaRequest{ChatId: chatId, MessageId: int(photos[i]), Photo: img}
+			if i == 0 {
+				edit.Caption, edit.CaptionEntities = caption.Text, caption.Entities
+			}
+
+			_, err = m.Client.EditMessageMedia(edit)
+		}
+
+		if err != nil && !isNotModified(err) {
+			return err
+		}
+
+		published.ImageHashes[i] = hash
+	}
+
+	for i, part := range parts {
+		_, err := m.Client.EditMessage(EditMessageRequest{
+			ChatId:    chatId,
+			MessageId: int(texts[i]),
+			Text:      part.Text,
+			Entities:  part.Entities,
+		})
+		if err != nil && !isNotModified(err) {
+			return err
+		}
+	}
+
+	return m.save(*published)
+}
+
+// Delete deletes every message of post, including all photos of an album. Deleting a post
+// that is not tracked or was already deleted does nothing.
+func (m *PostManager) Delete(post PostRequest) error {
+	published, err := m.lookup(post)
+	if errors.Is(err, ErrPostNotFound) {
+		return nil
+	}
+	if err != nil || published.Deleted {
+		return err
+	}
+
+	ids := make([]int, len(published.MessageIds))
+	for i, id := range published.MessageIds {
+		ids[i] = int(id)
+	}
+
+	if err := m.Client.DeleteMessages(published.ChatId, ids); err != nil {
+		return err
+	}
+
+	published.Deleted = true
+
+	return m.save(*published)
+}
+
+// lookup returns the tracked messages of post, tracking a published post on first use.
+func (m *PostManager) lookup(post PostRequest) (*PublishedPost, error) {
+	published, err := m.load(post.Id)
+	if !errors.Is(err, ErrPostNotFound) || post.Status != PostStatusPublished {
+		return published, err
+	}
+
+	if err := m.Track(post); err != nil {
+		return nil, err
+	}
+
+	return m.load(post.Id)
+}
+
+func (m *PostManager) save(published PublishedPost) error {
+	data, err := json.Marshal(published)
+	if err != nil {
+		return err
+	}
+
+	return m.Storage.Set(m.key(published.PostId), data, 0)
+}
+
+func (m *PostManager) key(postId int64) string {
+	return m.Prefix + "post:" + strconv.FormatInt(postId, 10)
+}
+
+// resolvePostType returns the post type PostScheduler publishes post as.
+func resolvePostType(post PostRequest) int64 {
+	if post.PostType != PostTypeAuto {
+		return post.PostType
+	}
+
+	switch len(post.Img) {
+	case 0:
+		return PostTypeText
+	case 1:
+		return PostTypePhoto
+	}
+
+	return PostTypeMediaGroup
+}
+
+// postTextParts splits text the way SendLongMessage does.
+func postTextParts(text, parseMode string, limit int) ([]TextPart, error) {
+	plain, entities, err := parseFormatted(text, parseMode, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return SplitText(plain, entities, limit), nil
+}
+
+// postCaptionParts splits a caption the way SendPhotoWithLongCaption does.
+func postCaptionParts(caption, parseMode string) (TextPart, []TextPart, error) {
+	first, rest, err := splitCaption(caption, parseMode, nil)
+	if err != nil || rest != nil {
+		return first, rest, err
+	}
+
+	plain, entities, err := parseFormatted(caption, parseMode, nil)
+	if err != nil {
+		return TextPart{}, nil, err
+	}
+
+	return TextPart{Text: plain, Entities: entities}, nil, nil
+}
+
+func imageHash(img string) string {
+	sum := sha256.Sum256([]byte(img))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// isNotModified reports whether err is the Bot API refusing an edit that changes nothing.
+func isNotModified(err error) bool {
+	var apiErr *APIError
+
+	return errors.As(err, &apiErr) && strings.Contains(apiErr.Description, "message is not modified")
+}
@@ -0,0 +1,140 @@
+package teledau
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPostManager_Update(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"editMessageText":    `{"ok":true,"result":{"message_id":10}}`,
+		"editMessageCaption": `{"ok":true,"result":{"message_id":20}}`,
+		"editMessageMedia":   `{"ok":true,"result":{"message_id":21}}`,
+	})
+	manager := NewPostManager(client, NewMemoryStorage())
+
+	text := PostRequest{Id: 1, ChatUsername: "@club", Text: "*Kickoff*", ParseMode: "MarkdownV2", Status: PostStatusPublished, MessageId: 10}
+	if err := manager.Update(text); err != nil {
+		t.Fatal(err)
+	}
+
+	var edit EditMessageRequest
+	if err := json.Unmarshal(transport.calls("editMessageText")[0].Body, &edit); err != nil {
+		t.Fatal(err)
+	}
+
+	if edit.ChatId != "@club" || edit.MessageId != 10 || edit.Text != "Kickoff" || len(edit.Entities) != 1 {
+		t.Errorf("unexpected edit: %+v", edit)
+	}
+
+	first := base64.StdEncoding.EncodeToString([]byte("first"))
+	second := base64.StdEncoding.EncodeToString([]byte("second"))
+	album := PostRequest{Id: 2, ChannelId: -100, Img: []string{first, second}, Text: "Lineup", Status: PostStatusPublished, MessageIds: []int64{20, 21}}
+	if err := manager.Track(album); err != nil {
+		t.Fatal(err)
+	}
+
+	album.Text = "New lineup"
+	album.Img[1] = base64.StdEncoding.EncodeToString([]byte("replaced"))
+	if err := manager.Update(album); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := transport.calls("editMessageCaption"); len(calls) != 1 || !strings.Contains(string(calls[0].Body), "New lineup") {
+		t.Errorf("caption should be edited once, got %d calls", len(calls))
+	}
+
+	media := transport.calls("editMessageMedia")
+	if len(media) != 1 {
+		t.Fatalf("only the changed photo should be replaced, got %d calls", len(media))
+	}
+
+	if form := readMultipartForm(t, media[0]); form["message_id"] != "21" || form["chat_id"] != "-100" {
+		t.Errorf("unexpected media edit: %v", form)
+	}
+
+	if err := manager.Update(album); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := transport.calls("editMessageMedia"); len(calls) != 1 {
+		t.Errorf("replaced photo should not be uploaded again, got %d calls", len(calls))
+	}
+
+	album.Img = album.Img[:1]
+	if err := manager.Update(album); !errors.Is(err, ErrPostLayoutChanged) {
+		t.Errorf("Update() with fewer images = %v, want ErrPostLayoutChanged", err)
+	}
+}
+
+func TestPostManager_UpdateAlbumWithoutMessageIds(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	manager := NewPostManager(client, NewMemoryStorage())
+
+	img := base64.StdEncoding.EncodeToString([]byte("photo"))
+	album := PostRequest{Id: 3, ChatUsername: "@club", Img: []string{img, img}, Text: "Lineup", Status: PostStatusPublished, MessageId: 30}
+	if err := manager.Track(album); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := manager.Update(album); !errors.Is(err, ErrPostLayoutChanged) {
+		t.Errorf("Update() of an album tracked by one message = %v, want ErrPostLayoutChanged", err)
+	}
+
+	if len(transport.calls("editMessageCaption")) != 0 || len(transport.calls("editMessageMedia")) != 0 {
+		t.Error("nothing should be edited")
+	}
+}
+
+func TestPostManager_UpdateNotModified(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	transport.handler = func(method string, body []byte) (int, string) {
+		return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message is not modified"}`
+	}
+
+	manager := NewPostManager(client, NewMemoryStorage())
+	post := PostRequest{Id: 1, ChatUsername: "@club", Text: "Same", Status: PostStatusPublished, MessageId: 10}
+	if err := manager.Update(post); err != nil {
+		t.Errorf("Update() of unchanged post = %v, want nil", err)
+	}
+}
+
+func TestPostManager_Delete(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	manager := NewPostManager(client, NewMemoryStorage())
+
+	album := PostRequest{Id: 2, ChannelId: -100, Img: []string{"a", "b"}, Status: PostStatusPublished, MessageIds: []int64{20, 21, 22}}
+	if err := manager.Delete(album); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := transport.calls("deleteMessages")
+	if len(calls) != 1 {
+		t.Fatalf("expected one deleteMessages call, got %d", len(calls))
+	}
+
+	var request deleteMessagesRequest
+	if err := json.Unmarshal(calls[0].Body, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if request.ChatId != "-100" || len(request.MessageIds) != 3 {
+		t.Errorf("unexpected request: %+v", request)
+	}
+
+	if _, err := manager.Published(album.Id); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("Published() after Delete = %v, want ErrPostNotFound", err)
+	}
+
+	if err := manager.Delete(album); err != nil {
+		t.Errorf("second Delete() = %v, want nil", err)
+	}
+
+	if calls := transport.calls("deleteMessages"); len(calls) != 1 {
+		t.Errorf("deleted post should not be deleted again, got %d calls", len(calls))
+	}
+}
@@ -0,0 +1,207 @@
+package teledau
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrPostNotFound is returned by PostQueue.Get for an unknown post id.
+var ErrPostNotFound = errors.New("post not found")
+
+// PostQueue stores the posts of a PostScheduler.
+type PostQueue interface {
+	// Save stores post, replacing the post with the same Id.
+	Save(post PostRequest) error
+	Get(id int64) (PostRequest, error)
+	// Due returns the queued posts whose time has come at now, oldest first.
+	Due(now time.Time) ([]PostRequest, error)
+}
+
+// postDueAt returns when a queued post should be attempted next.
+func postDueAt(post PostRequest) (time.Time, error) {
+	if len(post.NextAttemptAt) > 0 {
+		return time.Parse(time.RFC3339, post.NextAttemptAt)
+	}
+
+	return time.Parse(time.RFC3339, post.PostedAt)
+}
+
+// duePosts filters queued posts due at now and sorts them by due time.
+func duePosts(posts []PostRequest, now time.Time) []PostRequest {
+	type duePost struct {
+		post PostRequest
+		at   time.Time
+	}
+
+	var due []duePost
+	for _, post := range posts {
+		if post.Status != PostStatusQueued {
+			continue
+		}
+
+		at, err := postDueAt(post)
+		if err != nil || at.After(now) {
+			continue
+		}
+
+		due = append(due, duePost{post: post, at: at})
+	}
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+
+	result := make([]PostRequest, len(due))
+	for i, d := range due {
+		result[i] = d.post
+	}
+
+	return result
+}
+
+// MemoryPostQueue keeps posts in process memory.
+type MemoryPostQueue struct {
+	mu    sync.Mutex
+	posts map[int64]PostRequest
+}
+
+func NewMemoryPostQueue() *MemoryPostQueue {
+	return &MemoryPostQueue{posts: make(map[int64]PostRequest)}
+}
+
+func (q *MemoryPostQueue) Save(post PostRequest) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.posts[post.Id] = post
+
+	return nil
+}
+
+func (q *MemoryPostQueue) Get(id int64) (PostRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	post, ok := q.posts[id]
+	if !ok {
+		return PostRequest{}, ErrPostNotFound
+	}
+
+	return post, nil
+}
+
+func (q *MemoryPostQueue) Due(now time.Time) ([]PostRequest, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	posts := make([]PostRequest, 0, len(q.posts))
+	for _, post := range q.posts {
+		posts = append(posts, post)
+	}
+
+	return duePosts(posts, now), nil
+}
+
+// StoragePostQueue keeps posts in a Storage, one key per post plus an index of the queued ones.
+type StoragePostQueue struct {
+	Storage Storage
+	// Prefix is prepended to every storage key.
+	Prefix string
+}
+
+func NewStoragePostQueue(storage Storage) *StoragePostQueue {
+	return &StoragePostQueue{Storage: storage, Prefix: "post_queue:"}
+}
+
+func (q *StoragePostQueue) Save(post PostRequest) error {
+	data, err := json.Marshal(post)
+	if err != nil {
+		return err
+	}
+
+	if err := q.Storage.Set(q.postKey(post.Id), data, 0); err != nil {
+		return err
+	}
+
+	return UpdateStorage(q.Storage, q.Prefix+"queued", 0, func(current []byte) ([]byte, error) {
+		queued := make(map[int64]bool)
+		if current != nil {
+			var ids []int64
+			if err := json.Unmarshal(current, &ids); err != nil {
+				return nil, err
+			}
+
+			for _, id := range ids {
+				queued[id] = true
+			}
+		}
+
+		if post.Status == PostStatusQueued {
+			queued[post.Id] = true
+		} else {
+			delete(queued, post.Id)
+		}
+
+		ids := make([]int64, 0, len(queued))
+		for id := range queued {
+			ids = append(ids, id)
+		}
+
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		return json.Marshal(ids)
+	})
+}
+
+func (q *StoragePostQueue) Get(id int64) (PostRequest, error) {
+	data, err := q.Storage.Get(q.postKey(id))
+	if errors.Is(err, ErrStorageNotFound) {
+		return PostRequest{}, ErrPostNotFound
+	}
+	if err != nil {
+		return PostRequest{}, err
+	}
+
+	var post PostRequest
+	if err := json.Unmarshal(data, &post); err != nil {
+		return PostRequest{}, err
+	}
+
+	return post, nil
+}
+
+func (q *StoragePostQueue) Due(now time.Time) ([]PostRequest, error) {
+	data, err := q.Storage.Get(q.Prefix + "queued")
+	if errors.Is(err, ErrStorageNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, err
+	}
+
+	posts := make([]PostRequest, 0, len(ids))
+	for _, id := range ids {
+		post, err := q.Get(id)
+		if errors.Is(err, ErrPostNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	return duePosts(posts, now), nil
+}
+
+func (q *StoragePostQueue) postKey(id int64) string {
+	return q.Prefix + "post:" + strconv.FormatInt(id, 10)
+}
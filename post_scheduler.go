@@ -0,0 +1,268 @@
+package teledau
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// PostScheduler publishes queued PostRequests at their PostedAt time. Text posts are sent with
+// SendLongMessage, posts with one image with SendPhotoWithLongCaption and posts with several images
+// as a media group, so long texts are split instead of rejected. The message ids and the status
+// are written back to the queue.
+//
+// Failed posts are retried with exponential backoff, honouring the flood-control delay of the Bot API,
+// until MaxAttempts is reached. Errors that a retry can't fix, such as a malformed request or a missing
+// chat, fail the post at once. A queue must only be served by one scheduler at a time.
+type PostScheduler struct {
+	Client Telegram
+	Queue  PostQueue
+	// Interval is how often Run checks the queue.
+	Interval    time.Duration
+	MaxAttempts int
+	// RetryDelay is the delay before the first retry; it doubles after every failed attempt.
+	RetryDelay time.Duration
+}
+
+func NewPostScheduler(client Telegram, queue PostQueue) *PostScheduler {
+	return &PostScheduler{
+		Client:      client,
+		Queue:       queue,
+		Interval:    10 * time.Second,
+		MaxAttempts: 5,
+		RetryDelay:  time.Minute,
+	}
+}
+
+// Schedule validates post and queues it. A post without Id gets one.
+func (s *PostScheduler) Schedule(post PostRequest) (PostRequest, error) {
+	if _, err := time.Parse(time.RFC3339, post.PostedAt); err != nil {
+		return PostRequest{}, fmt.Errorf("post %d: posted_at: %w", post.Id, err)
+	}
+
+	if post.PostType == PostTypePhoto && len(post.Img) != 1 || post.PostType == PostTypeMediaGroup && len(post.Img) < 2 {
+		return PostRequest{}, fmt.Errorf("post %d: post type %d does not match %d images", post.Id, post.PostType, len(post.Img))
+	}
+
+	if post.Id == 0 {
+		post.Id = time.Now().UnixNano()
+	}
+
+	post.Status = PostStatusQueued
+	post.Attempts, post.LastError, post.NextAttemptAt = 0, "", ""
+	post.MessageIds = nil
+
+	return post, s.Queue.Save(post)
+}
+
+// Cancel stops a queued post from being published.
+func (s *PostScheduler) Cancel(id int64) error {
+	post, err := s.Queue.Get(id)
+	if err != nil {
+		return err
+	}
+
+	if post.Status != PostStatusQueued {
+		return fmt.Errorf("post %d is not queued", id)
+	}
+
+	post.Status = PostStatusCanceled
+
+	return s.Queue.Save(post)
+}
+
+// Run publishes due posts every Interval until ctx is done.
+func (s *PostScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunOnce(time.Now()); err != nil {
+			log.Printf("Error publishing scheduled posts: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce publishes the posts due at now. Publishing errors are recorded in the posts;
+// only queue errors are returned.
+func (s *PostScheduler) RunOnce(now time.Time) error {
+	posts, err := s.Queue.Due(now)
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		if err := s.Queue.Save(s.publish(post, now)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *PostScheduler) publish(post PostRequest, now time.Time) PostRequest {
+	post.Attempts++
+
+	// Messages sent before an error are kept, so a retry only sends the rest of the post.
+	messageIds, err := sendPost(s.Client, post)
+	if len(messageIds) > 0 {
+		post.MessageId, post.MessageIds = messageIds[0], messageIds
+	}
+
+	if err == nil {
+		post.Status = PostStatusPublished
+		post.LastError, post.NextAttemptAt = "", ""
+		post.PublishedAt = now.Format(time.RFC3339)
+
+		return post
+	}
+
+	log.Printf("Error publishing post %d (attempt %d): %v", post.Id, post.Attempts, err)
+	post.LastError = err.Error()
+
+	var apiErr *APIError
+	permanent := errors.As(err, &apiErr) && !apiErr.Temporary()
+	if permanent || post.Attempts >= s.MaxAttempts {
+		post.Status = PostStatusFailed
+		post.NextAttemptAt = ""
+
+		return post
+	}
+
+	delay := s.RetryDelay << (post.Attempts - 1)
+	if apiErr != nil && time.Duration(apiErr.RetryAfter)*time.Second > delay {
+		delay = time.Duration(apiErr.RetryAfter) * time.Second
+	}
+
+	post.NextAttemptAt = now.Add(delay).Format(time.RFC3339)
+
+	return post
+}
+
+// sendPost publishes post and returns the ids of the messages it produced. post.MessageIds are
+// the messages an interrupted earlier attempt already sent; only the parts after them are sent then.
+// On error the ids of the messages sent so far are returned along with it.
+func sendPost(client Telegram, post PostRequest) ([]int64, error) {
+	if len(post.MessageIds) > 0 {
+		return resumePost(client, post)
+	}
+
+	var messages []Message
+	var err error
+
+	switch resolvePostType(post) {
+	case PostTypeText:
+		var responses []SendMessageResponse
+		responses, err = client.SendLongMessage(MessageRequest{
+			ChatId:          post.ChatId(),
+			Text:            post.Text,
+			ParseMode:       post.ParseMode,
+			ReplyParameters: post.ReplyParameters(),
+		})
+
+		for _, response := range responses {
+			messages = append(messages, response.Result)
+		}
+	case PostTypePhoto:
+		var responses []SendMessageResponse
+		responses, err = client.SendPhotoWithLongCaption(PhotoRequest{
+			ChatId:          post.ChatId(),
+			Photo:           post.Img[0],
+			Caption:         post.Text,
+			ParseMode:       post.ParseMode,
+			ReplyParameters: post.ReplyParameters(),
+		})
+
+		for _, response := range responses {
+			messages = append(messages, response.Result)
+		}
+	case PostTypeMediaGroup:
+		var group *MediaPostResponse
+		var followUps []SendMessageResponse
+		group, followUps, err = client.SendPhotoGroupWithLongCaption(MediaGroupRequest{
+			ChatId:          post.ChatId(),
+			Photos:          post.Img,
+			Caption:         post.Text,
+			ParseMode:       post.ParseMode,
+			ReplyParameters: post.ReplyParameters(),
+		})
+
+		if group != nil {
+			messages = append(messages, group.Result...)
+		}
+
+		for _, response := range followUps {
+			messages = append(messages, response.Result)
+		}
+	default:
+		return nil, fmt.Errorf("unknown post type %d", post.PostType)
+	}
+
+	ids := make([]int64, 0, len(messages))
+	for _, message := range messages {
+		if message.MessageId != 0 {
+			ids = append(ids, int64(message.MessageId))
+		}
+	}
+
+	if err != nil {
+		return ids, err
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("no messages were sent")
+	}
+
+	return ids, nil
+}
+
+// resumePost sends the text parts of post that follow its already sent messages. The parts are
+// split like SendLongMessage and SendPhotoWithLongCaption split them.
+func resumePost(client Telegram, post PostRequest) ([]int64, error) {
+	ids := append([]int64(nil), post.MessageIds...)
+
+	var parts []TextPart
+	var err error
+	sent := len(ids)
+	message := MessageRequest{ChatId: post.ChatId()}
+
+	switch resolvePostType(post) {
+	case PostTypeText:
+		parts, err = postTextParts(post.Text, post.ParseMode, MaxMessageLength)
+		message.ReplyParameters = post.ReplyParameters()
+	case PostTypePhoto, PostTypeMediaGroup:
+		// An album is sent in one request, so its photos are all sent or none.
+		_, parts, err = postCaptionParts(post.Text, post.ParseMode)
+		sent -= len(post.Img)
+	default:
+		return ids, fmt.Errorf("unknown post type %d", post.PostType)
+	}
+	if err != nil {
+		return ids, err
+	}
+
+	if sent < 0 || sent > len(parts) {
+		return ids, fmt.Errorf("post %d: %d sent messages don't match its layout", post.Id, len(ids))
+	}
+
+	for _, part := range parts[sent:] {
+		message.Text, message.Entities = part.Text, part.Entities
+
+		response, err := client.SendMessage(message)
+		if err != nil {
+			return ids, err
+		}
+
+		ids = append(ids, int64(response.Result.MessageId))
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,170 @@
+package teledau
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostScheduler(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"sendMessage":    `{"ok":true,"result":{"message_id":10}}`,
+		"sendMediaGroup": `{"ok":true,"result":[{"message_id":20},{"message_id":21}]}`,
+	})
+
+	for name, queue := range map[string]PostQueue{"memory": NewMemoryPostQueue(), "storage": NewStoragePostQueue(NewMemoryStorage())} {
+		scheduler := NewPostScheduler(client, queue)
+		now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		image := base64.StdEncoding.EncodeToString([]byte("jpeg"))
+
+		text, err := scheduler.Schedule(PostRequest{Id: 1, ChatUsername: "@club", Text: "Kick-off", PostedAt: now.Format(time.RFC3339), ReplyToMessageId: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		album, err := scheduler.Schedule(PostRequest{Id: 2, ChannelId: -100, Img: []string{image, image}, PostedAt: now.Add(-time.Minute).Format(time.RFC3339)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		later, err := scheduler.Schedule(PostRequest{Id: 3, ChatUsername: "@club", Text: "Later", PostedAt: now.Add(time.Hour).Format(time.RFC3339)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := scheduler.RunOnce(now); err != nil {
+			t.Fatal(err)
+		}
+
+		if text, _ = queue.Get(text.Id); text.Status != PostStatusPublished || text.MessageId != 10 || text.PublishedAt != now.Format(time.RFC3339) {
+			t.Errorf("%s: unexpected text post: %+v", name, text)
+		}
+
+		if album, _ = queue.Get(album.Id); album.Status != PostStatusPublished || album.MessageId != 20 || len(album.MessageIds) != 2 {
+			t.Errorf("%s: unexpected album post: %+v", name, album)
+		}
+
+		if later, _ = queue.Get(later.Id); later.Status != PostStatusQueued || later.Attempts != 0 {
+			t.Errorf("%s: post scheduled for later was touched: %+v", name, later)
+		}
+
+		if due, _ := queue.Due(now.Add(2 * time.Hour)); len(due) != 1 || due[0].Id != 3 {
+			t.Errorf("%s: Due() = %+v, want only post 3", name, due)
+		}
+	}
+
+	var sent MessageRequest
+	if err := json.Unmarshal(transport.calls("sendMessage")[0].Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.ChatId != "@club" || sent.ReplyParameters == nil || sent.ReplyParameters.MessageId != 5 {
+		t.Errorf("unexpected sendMessage request: %+v", sent)
+	}
+
+	if form := readMultipartForm(t, transport.calls("sendMediaGroup")[0]); form[TgFieldChatId] != "-100" {
+		t.Errorf("unexpected sendMediaGroup chat: %q", form[TgFieldChatId])
+	}
+}
+
+func TestPostSchedulerRetries(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	status := 429
+	transport.handler = func(method string, body []byte) (int, string) {
+		if status == 429 {
+			return 429, `{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 300","parameters":{"retry_after":300}}`
+		}
+
+		return 400, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+	}
+
+	queue := NewMemoryPostQueue()
+	scheduler := NewPostScheduler(client, queue)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	post, err := scheduler.Schedule(PostRequest{ChatUsername: "@club", Text: "Kick-off", PostedAt: now.Format(time.RFC3339)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scheduler.RunOnce(now); err != nil {
+		t.Fatal(err)
+	}
+
+	post, _ = queue.Get(post.Id)
+	if post.Status != PostStatusQueued || post.Attempts != 1 || post.NextAttemptAt != now.Add(5*time.Minute).Format(time.RFC3339) {
+		t.Fatalf("flood-controlled post should be retried after retry_after: %+v", post)
+	}
+
+	if due, _ := queue.Due(now.Add(time.Minute)); len(due) != 0 {
+		t.Error("post must not be due before its next attempt")
+	}
+
+	status = 400
+	if err := scheduler.RunOnce(now.Add(5 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	post, _ = queue.Get(post.Id)
+	if post.Status != PostStatusFailed || post.Attempts != 2 || post.LastError == "" {
+		t.Errorf("post should fail on a permanent error: %+v", post)
+	}
+}
+
+func TestPostSchedulerResumesPartlySentPost(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	calls := 0
+	transport.handler = func(method string, body []byte) (int, string) {
+		calls++
+		if calls == 2 {
+			return 500, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`
+		}
+
+		return 200, fmt.Sprintf(`{"ok":true,"result":{"message_id":%d}}`, 10+calls)
+	}
+
+	queue := NewMemoryPostQueue()
+	scheduler := NewPostScheduler(client, queue)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	long := strings.Repeat("a", MaxMessageLength) + " " + strings.Repeat("b", 10)
+	post, err := scheduler.Schedule(PostRequest{ChatUsername: "@club", Text: long, PostedAt: now.Format(time.RFC3339)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scheduler.RunOnce(now); err != nil {
+		t.Fatal(err)
+	}
+
+	post, _ = queue.Get(post.Id)
+	if post.Status != PostStatusQueued || len(post.MessageIds) != 1 || post.MessageIds[0] != 11 {
+		t.Fatalf("the sent part should be kept for the retry: %+v", post)
+	}
+
+	if err := scheduler.RunOnce(now.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	post, _ = queue.Get(post.Id)
+	if post.Status != PostStatusPublished || len(post.MessageIds) != 2 || post.MessageIds[1] != 13 {
+		t.Fatalf("unexpected post after the retry: %+v", post)
+	}
+
+	sent := transport.calls("sendMessage")
+	if len(sent) != 3 {
+		t.Fatalf("expected 3 sendMessage calls, got %d", len(sent))
+	}
+
+	var retried MessageRequest
+	if err := json.Unmarshal(sent[2].Body, &retried); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(retried.Text, "b") {
+		t.Errorf("retry should send the second part only, got %.20q", retried.Text)
+	}
+}
@@ -0,0 +1,292 @@
+package teledau
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// QuizQuestion is one question of a quiz; CorrectOptionId indexes Options.
+type QuizQuestion struct {
+	Question        string   `json:"question"`
+	Options         []string `json:"options"`
+	CorrectOptionId int      `json:"correct_option_id"`
+	Explanation     string   `json:"explanation,omitempty"`
+}
+
+// QuizScore is the standing of one player in a chat.
+type QuizScore struct {
+	UserId    int64  `json:"user_id"`
+	Username  string `json:"username,omitempty"`
+	FirstName string `json:"first_name,omitempty"`
+	Points    int    `json:"points"`
+	Correct   int    `json:"correct"`
+	Answered  int    `json:"answered"`
+}
+
+// Name returns the first name of the player, or the username when it is empty.
+func (s QuizScore) Name() string {
+	if len(s.FirstName) > 0 {
+		return s.FirstName
+	}
+
+	if len(s.Username) > 0 {
+		return "@" + s.Username
+	}
+
+	return strconv.FormatInt(s.UserId, 10)
+}
+
+// Quiz runs quizzes as Telegram quiz polls and keeps a leaderboard per chat.
+//
+// A correct answer earns Points plus up to SpeedBonus more, decreasing linearly to nothing at the end
+// of OpenPeriod, so faster players rank higher. Polls are scored once they close; feed HandleUpdate
+// every poll and poll_answer update. Quiz polls must not be anonymous for the answers to reach the bot.
+//
+// The Bot API refuses non-anonymous polls in channels, so a quiz can't run in a channel itself:
+// Publish returns ErrQuizChannel there. Run it in the channel's linked discussion group instead.
+type Quiz struct {
+	Client Telegram
+	Tally  *PollTally
+	// OpenPeriod is how long each question stays open, between 5 and 600 seconds.
+	OpenPeriod time.Duration
+	// Interval is the pause between two questions of a series.
+	Interval   time.Duration
+	Points     int
+	SpeedBonus int
+}
+
+// ErrQuizChannel is returned by Quiz.Publish for channel chats.
+var ErrQuizChannel = errors.New("quizzes can't run in channels, whose polls are anonymous")
+
+func NewQuiz(client Telegram, storage Storage) *Quiz {
+	return &Quiz{
+		Client:     client,
+		Tally:      &PollTally{Storage: storage, Prefix: "quiz:"},
+		OpenPeriod: time.Minute,
+		Interval:   time.Minute,
+		Points:     100,
+		SpeedBonus: 50,
+	}
+}
+
+// Publish sends questions to chatId one after another, Interval apart, and returns the poll messages.
+// On error the messages sent so far are returned along with it.
+func (q *Quiz) Publish(ctx context.Context, chatId string, questions []QuizQuestion) ([]Message, error) {
+	chat, err := q.Client.GetChat(chatId)
+	if err != nil {
+		return nil, err
+	}
+
+	if chat.GetChat.Type == ChatTypeChannel {
+		if chat.GetChat.LinkedChatId != 0 {
+			return nil, fmt.Errorf("%w: %s, use its discussion group %d", ErrQuizChannel, chatId, chat.GetChat.LinkedChatId)
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrQuizChannel, chatId)
+	}
+
+	messages := make([]Message, 0, len(questions))
+
+	for i, question := range questions {
+		if i > 0 {
+			if err := sleepContext(ctx, q.Interval); err != nil {
+				return messages, err
+			}
+		}
+
+		response, err := q.Client.SendPoll(PollRequest{
+			ChatId:          chatId,
+			Question:        question.Question,
+			Options:         question.Options,
+			Type:            PollTypeQuiz,
+			CorrectOptionId: question.CorrectOptionId,
+			Explanation:     question.Explanation,
+			OpenPeriod:      int(q.OpenPeriod / time.Second),
+		})
+		if err != nil {
+			return messages, err
+		}
+
+		if err := q.Tally.Track(response.Result); err != nil {
+			return messages, err
+		}
+
+		messages = append(messages, response.Result)
+	}
+
+	return messages, nil
+}
+
+// HandleUpdate records poll answers and scores a quiz when it closes.
+func (q *Quiz) HandleUpdate(u Update) error {
+	if err := q.Tally.HandleUpdate(u); err != nil {
+		return err
+	}
+
+	if u.Poll == nil || !u.Poll.IsClosed || u.Poll.Type != PollTypeQuiz {
+		return nil
+	}
+
+	if err := q.Score(u.Poll.Id); err != nil && !errors.Is(err, ErrStorageNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// Score adds the answers of a tracked quiz poll to the leaderboard of its chat. A poll is only scored once.
+func (q *Quiz) Score(pollId string) error {
+	results, err := q.Tally.Results(pollId)
+	if err != nil {
+		return err
+	}
+
+	tracked := results.Poll
+	if tracked.Poll.Type != PollTypeQuiz || tracked.ChatId == 0 {
+		return fmt.Errorf("poll %s is not a tracked quiz", pollId)
+	}
+
+	scoredKey := q.Tally.Prefix + "scored:" + pollId
+	ok, err := q.Tally.Storage.CompareAndSwap(scoredKey, nil, []byte("1"), 0)
+	if err != nil || !ok {
+		return err
+	}
+
+	err = UpdateStorage(q.Tally.Storage, q.leaderboardKey(tracked.ChatId), 0, func(current []byte) ([]byte, error) {
+		scores := make(map[int64]*QuizScore)
+		if current != nil {
+			if err := json.Unmarshal(current, &scores); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, vote := range results.Votes {
+			score, ok := scores[vote.UserId]
+			if !ok {
+				score = &QuizScore{UserId: vote.UserId}
+				scores[vote.UserId] = score
+			}
+
+			score.Username, score.FirstName = vote.Username, vote.FirstName
+			score.Answered++
+
+			if len(vote.OptionIds) == 1 && vote.OptionIds[0] == tracked.Poll.CorrectOptionId {
+				score.Correct++
+				score.Points += q.points(vote.VotedAt.Sub(tracked.SentAt))
+			}
+		}
+
+		return json.Marshal(scores)
+	})
+	if err != nil {
+		// Let a later call score the poll again.
+		_ = q.Tally.Storage.Delete(scoredKey)
+	}
+
+	return err
+}
+
+// Leaderboard returns the scores of a chat, best first.
+func (q *Quiz) Leaderboard(chatId int64) ([]QuizScore, error) {
+	data, err := q.Tally.Storage.Get(q.leaderboardKey(chatId))
+	if errors.Is(err, ErrStorageNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var scores map[int64]*QuizScore
+	if err := json.Unmarshal(data, &scores); err != nil {
+		return nil, err
+	}
+
+	leaderboard := make([]QuizScore, 0, len(scores))
+	for _, score := range scores {
+		leaderboard = append(leaderboard, *score)
+	}
+
+	sort.Slice(leaderboard, func(i, j int) bool {
+		a, b := leaderboard[i], leaderboard[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+
+		if a.Correct != b.Correct {
+			return a.Correct > b.Correct
+		}
+
+		return a.UserId < b.UserId
+	})
+
+	return leaderboard, nil
+}
+
+// ResetLeaderboard clears the scores of a chat, e.g. at the start of a new week.
+func (q *Quiz) ResetLeaderboard(chatId int64) error {
+	return q.Tally.Storage.Delete(q.leaderboardKey(chatId))
+}
+
+// Standings formats the top players of a chat as a message with entities.
+func (q *Quiz) Standings(chatId int64, top int) (string, []MessageEntity, error) {
+	leaderboard, err := q.Leaderboard(chatId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	b := NewTextBuilder().Bold("Quiz standings").Text("\n")
+	if len(leaderboard) == 0 {
+		b.Text("\nNo answers yet.")
+	}
+
+	for i, score := range leaderboard {
+		if top > 0 && i == top {
+			break
+		}
+
+		b.Text(fmt.Sprintf("\n%d. ", i+1))
+		if score.UserId > 0 {
+			b.Mention(score.Name(), From{Id: score.UserId})
+		} else {
+			b.Text(score.Name())
+		}
+
+		b.Text(" — ").Bold(strconv.Itoa(score.Points)).Text(fmt.Sprintf(" (%d/%d correct)", score.Correct, score.Answered))
+	}
+
+	text, entities := b.Build()
+
+	return text, entities, nil
+}
+
+// PostStandings sends the standings of a chat to it.
+func (q *Quiz) PostStandings(chatId int64, top int) (SendMessageResponse, error) {
+	text, entities, err := q.Standings(chatId, top)
+	if err != nil {
+		return SendMessageResponse{}, err
+	}
+
+	return q.Client.SendMessage(MessageRequest{ChatId: strconv.FormatInt(chatId, 10), Text: text, Entities: entities})
+}
+
+// points returns the points for a correct answer given after elapsed.
+func (q *Quiz) points(elapsed time.Duration) int {
+	if q.OpenPeriod <= 0 || elapsed >= q.OpenPeriod {
+		return q.Points
+	}
+
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	return q.Points + int(int64(q.SpeedBonus)*int64(q.OpenPeriod-elapsed)/int64(q.OpenPeriod))
+}
+
+func (q *Quiz) leaderboardKey(chatId int64) string {
+	return q.Tally.Prefix + "leaderboard:" + strconv.FormatInt(chatId, 10)
+}
@@ -0,0 +1,114 @@
+package teledau
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuiz(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"sendPoll":    `{"ok":true,"result":{"message_id":9,"date":1709316000,"chat":{"id":-100},"poll":{"id":"p1","type":"quiz","correct_option_id":1,"options":[{"text":"1"},{"text":"2"}]}}}`,
+		"sendMessage": `{"ok":true,"result":{"message_id":10}}`,
+		"getChat":     `{"ok":true,"result":{"id":-100,"title":"Club fans","type":"supergroup"}}`,
+	})
+
+	quiz := NewQuiz(client, NewMemoryStorage())
+	sent := time.Unix(1709316000, 0)
+
+	if _, err := quiz.Publish(context.Background(), "@club", []QuizQuestion{{Question: "Goals in the final?", Options: []string{"1", "2"}, CorrectOptionId: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var request map[string]interface{}
+	if err := json.Unmarshal(transport.calls("sendPoll")[0].Body, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if request["type"] != PollTypeQuiz || request["correct_option_id"] != float64(1) || request["open_period"] != float64(60) {
+		t.Errorf("unexpected sendPoll request: %v", request)
+	}
+
+	answers := []struct {
+		answer PollAnswer
+		after  time.Duration
+	}{
+		{PollAnswer{PollId: "p1", User: &From{Id: 1, FirstName: "Ann"}, OptionIds: []int{1}}, 30 * time.Second},
+		{PollAnswer{PollId: "p1", User: &From{Id: 2, FirstName: "Bob"}, OptionIds: []int{1}}, 0},
+		{PollAnswer{PollId: "p1", User: &From{Id: 3, FirstName: "Cat"}, OptionIds: []int{0}}, time.Second},
+	}
+	for _, a := range answers {
+		if err := quiz.Tally.RecordAnswer(a.answer, sent.Add(a.after)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closed := Update{Poll: &Poll{Id: "p1", Type: PollTypeQuiz, CorrectOptionId: 1, IsClosed: true, Options: []Options{{Text: "1"}, {Text: "2"}}}}
+	for i := 0; i < 2; i++ {
+		if err := quiz.HandleUpdate(closed); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	leaderboard, err := quiz.Leaderboard(-100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(leaderboard) != 3 {
+		t.Fatalf("got %d scores, want 3", len(leaderboard))
+	}
+
+	if bob := leaderboard[0]; bob.UserId != 2 || bob.Points != 150 || bob.Correct != 1 {
+		t.Errorf("fastest correct answer should lead with 150 points, got %+v", bob)
+	}
+
+	if ann := leaderboard[1]; ann.UserId != 1 || ann.Points != 125 {
+		t.Errorf("answer after half the open period should get 125 points, got %+v", ann)
+	}
+
+	if cat := leaderboard[2]; cat.Points != 0 || cat.Answered != 1 {
+		t.Errorf("wrong answer should score nothing, got %+v", cat)
+	}
+
+	if _, err := quiz.PostStandings(-100, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	var standings MessageRequest
+	if err := json.Unmarshal(transport.calls("sendMessage")[0].Body, &standings); err != nil {
+		t.Fatal(err)
+	}
+
+	if standings.ChatId != "-100" || !strings.Contains(standings.Text, "1. Bob — 150 (1/1 correct)") || strings.Contains(standings.Text, "Cat") {
+		t.Errorf("unexpected standings: %q", standings.Text)
+	}
+
+	if err := quiz.ResetLeaderboard(-100); err != nil {
+		t.Fatal(err)
+	}
+
+	if leaderboard, _ := quiz.Leaderboard(-100); len(leaderboard) != 0 {
+		t.Errorf("leaderboard should be empty after reset, got %+v", leaderboard)
+	}
+}
+
+func TestQuiz_PublishToChannel(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"getChat": `{"ok":true,"result":{"id":-100,"title":"Club","type":"channel","linked_chat_id":-200}}`,
+	})
+
+	quiz := NewQuiz(client, NewMemoryStorage())
+
+	messages, err := quiz.Publish(context.Background(), "@club", []QuizQuestion{{Question: "Goals in the final?", Options: []string{"1", "2"}}})
+	if !errors.Is(err, ErrQuizChannel) || !strings.Contains(err.Error(), "-200") {
+		t.Fatalf("expected ErrQuizChannel naming the discussion group, got %v", err)
+	}
+
+	if len(messages) != 0 || len(transport.calls("sendPoll")) != 0 {
+		t.Fatal("no poll should be sent to a channel")
+	}
+}
@@ -0,0 +1,81 @@
+package teledau
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces requests to the Bot API.
+type RateLimiter interface {
+	// Wait blocks until a message may be sent to chatId or ctx is done.
+	Wait(ctx context.Context, chatId string) error
+}
+
+// IntervalRateLimiter keeps at least Interval between any two messages and ChatInterval
+// between two messages to the same chat. The defaults of NewIntervalRateLimiter follow the limits
+// of the Bot API: about 30 messages per second overall and 20 messages per minute to one group.
+type IntervalRateLimiter struct {
+	Interval     time.Duration
+	ChatInterval time.Duration
+
+	mu       sync.Mutex
+	next     time.Time
+	chatNext map[string]time.Time
+}
+
+func NewIntervalRateLimiter() *IntervalRateLimiter {
+	return &IntervalRateLimiter{Interval: time.Second / 30, ChatInterval: 3 * time.Second}
+}
+
+func (l *IntervalRateLimiter) Wait(ctx context.Context, chatId string) error {
+	return sleepContext(ctx, l.reserve(chatId, time.Now()))
+}
+
+// reserve books the earliest slot for chatId and returns how long to wait for it.
+func (l *IntervalRateLimiter) reserve(chatId string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.chatNext == nil {
+		l.chatNext = make(map[string]time.Time)
+	}
+
+	if len(l.chatNext) > 1000 {
+		for id, next := range l.chatNext {
+			if !next.After(now) {
+				delete(l.chatNext, id)
+			}
+		}
+	}
+
+	at := now
+	if l.next.After(at) {
+		at = l.next
+	}
+	if next := l.chatNext[chatId]; next.After(at) {
+		at = next
+	}
+
+	l.next = at.Add(l.Interval)
+	l.chatNext[chatId] = at.Add(l.ChatInterval)
+
+	return at.Sub(now)
+}
+
+// sleepContext waits for delay, returning early with the error of ctx when it is done.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,31 @@
+package teledau
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestIntervalRateLimiter(t *testing.T) {
+	limiter := &IntervalRateLimiter{Interval: time.Second, ChatInterval: time.Minute}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if delay := limiter.reserve("@a", now); delay != 0 {
+		t.Errorf("first delay = %v, want 0", delay)
+	}
+
+	if delay := limiter.reserve("@b", now); delay != time.Second {
+		t.Errorf("delay for another chat = %v, want 1s", delay)
+	}
+
+	if delay := limiter.reserve("@a", now); delay != time.Minute {
+		t.Errorf("delay for the same chat = %v, want 1m", delay)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := limiter.Wait(ctx, "@a"); err != context.Canceled {
+		t.Errorf("Wait() with canceled context = %v, want context.Canceled", err)
+	}
+}
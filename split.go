@@ -0,0 +1,165 @@
+package teledau
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TextPart is a piece of a text produced by SplitText. Entity offsets are relative to the piece.
+type TextPart struct {
+	Text     string
+	Entities []MessageEntity
+}
+
+// SplitText splits text into parts of at most limit UTF-16 code units. It cuts after a paragraph,
+// a line, a sentence or a word, in that order of preference, and avoids cutting inside entities.
+// An entity is only split when no boundary outside of entities fits; both halves then keep its type.
+// Whitespace around a cut is dropped.
+func SplitText(text string, entities []MessageEntity, limit int) []TextPart {
+	s := newTextSplitter(text, entities)
+
+	var parts []TextPart
+	for {
+		part, ok := s.next(limit)
+		if !ok {
+			return parts
+		}
+
+		parts = append(parts, part)
+	}
+}
+
+// textSplitter cuts parts off the front of a text. Positions are rune indexes.
+type textSplitter struct {
+	text     string
+	entities []MessageEntity
+	runes    []rune
+	bytePos  []int
+	utf16Pos []int
+	start    int
+}
+
+func newTextSplitter(text string, entities []MessageEntity) *textSplitter {
+	s := &textSplitter{text: text, entities: entities}
+
+	offset := 0
+	for i, r := range text {
+		s.runes = append(s.runes, r)
+		s.bytePos = append(s.bytePos, i)
+		s.utf16Pos = append(s.utf16Pos, offset)
+		offset += utf16RuneLen(r)
+	}
+
+	s.bytePos = append(s.bytePos, len(text))
+	s.utf16Pos = append(s.utf16Pos, offset)
+
+	return s
+}
+
+// next returns the next part of at most limit UTF-16 code units, or false when the text is exhausted.
+func (s *textSplitter) next(limit int) (TextPart, bool) {
+	n := len(s.runes)
+	if s.start >= n {
+		return TextPart{}, false
+	}
+
+	end := n
+	if s.utf16Pos[n]-s.utf16Pos[s.start] > limit {
+		end = s.cut(limit)
+	}
+
+	partEnd := end
+	if end < n {
+		for partEnd > s.start+1 && unicode.IsSpace(s.runes[partEnd-1]) {
+			partEnd--
+		}
+	}
+
+	part := s.part(s.start, partEnd)
+
+	s.start = end
+	for s.start < n && unicode.IsSpace(s.runes[s.start]) {
+		s.start++
+	}
+
+	return part, true
+}
+
+// cut returns the rune index where the part starting at s.start should end.
+func (s *textSplitter) cut(limit int) int {
+	maxEnd := s.start + 1
+	for maxEnd < len(s.runes) && s.utf16Pos[maxEnd+1]-s.utf16Pos[s.start] <= limit {
+		maxEnd++
+	}
+
+	// Paragraph, line and sentence boundaries only count in the second half of a part,
+	// so a short first paragraph does not produce a tiny message.
+	minEnd := s.start + (maxEnd-s.start)/2
+
+	boundaries := []func(i int) bool{
+		func(i int) bool { return i >= minEnd && s.runes[i-1] == '\n' && i >= 2 && s.runes[i-2] == '\n' },
+		func(i int) bool { return i >= minEnd && s.runes[i-1] == '\n' },
+		func(i int) bool {
+			return i >= minEnd && i >= 2 && unicode.IsSpace(s.runes[i-1]) && strings.ContainsRune(".!?…", s.runes[i-2])
+		},
+		func(i int) bool { return unicode.IsSpace(s.runes[i-1]) },
+	}
+
+	for _, keepEntities := range []bool{true, false} {
+		for _, isBoundary := range boundaries {
+			for i := maxEnd; i > s.start; i-- {
+				if isBoundary(i) && (!keepEntities || !s.insideEntity(s.utf16Pos[i])) {
+					return i
+				}
+			}
+		}
+	}
+
+	return maxEnd
+}
+
+func (s *textSplitter) insideEntity(offset int) bool {
+	for _, entity := range s.entities {
+		if entity.Offset < offset && offset < entity.Offset+entity.Length {
+			return true
+		}
+	}
+
+	return false
+}
+
+// part returns the runes [from, to) with the entities clipped to them.
+func (s *textSplitter) part(from, to int) TextPart {
+	begin, end := s.utf16Pos[from], s.utf16Pos[to]
+
+	part := TextPart{Text: s.text[s.bytePos[from]:s.bytePos[to]]}
+	for _, entity := range s.entities {
+		entityBegin, entityEnd := entity.Offset, entity.Offset+entity.Length
+		if entityBegin < begin {
+			entityBegin = begin
+		}
+
+		if entityEnd > end {
+			entityEnd = end
+		}
+
+		if entityEnd <= entityBegin {
+			continue
+		}
+
+		entity.Offset = entityBegin - begin
+		entity.Length = entityEnd - entityBegin
+		part.Entities = append(part.Entities, entity)
+	}
+
+	return part
+}
+
+// parseFormatted returns text and entities for a message that uses either entities or a parse mode.
+func parseFormatted(text, parseMode string, entities []MessageEntity) (string, []MessageEntity, error) {
+	if len(entities) > 0 || parseMode == "" {
+		return text, entities, nil
+	}
+
+	return Parse(parseMode, text)
+}
@@ -0,0 +1,130 @@
+package teledau
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitText(t *testing.T) {
+	text := "First paragraph here.\n\nSecond one. It has two sentences."
+
+	parts := SplitText(text, nil, 40)
+	want := []TextPart{
+		{Text: "First paragraph here."},
+		{Text: "Second one. It has two sentences."},
+	}
+
+	if !reflect.DeepEqual(parts, want) {
+		t.Errorf("SplitText() = %+v, want %+v", parts, want)
+	}
+
+	parts = SplitText("One sentence. Another sentence here.", nil, 22)
+	if len(parts) != 2 || parts[0].Text != "One sentence." || parts[1].Text != "Another sentence here." {
+		t.Errorf("SplitText() = %+v", parts)
+	}
+}
+
+func TestSplitTextKeepsEntities(t *testing.T) {
+	b := NewTextBuilder()
+	b.Text("😀 aaa ").Bold("bold words").Text(" tail")
+	text, entities := b.Build()
+
+	// The best word boundary within the limit is inside the bold entity, so the cut moves before it.
+	parts := SplitText(text, entities, 12)
+	want := []TextPart{
+		{Text: "😀 aaa"},
+		{Text: "bold words", Entities: []MessageEntity{{Type: EntityTypeBold, Offset: 0, Length: 10}}},
+		{Text: "tail"},
+	}
+
+	if !reflect.DeepEqual(parts, want) {
+		t.Errorf("SplitText() = %+v, want %+v", parts, want)
+	}
+
+	// An entity longer than the limit is split and both halves keep the formatting.
+	parts = SplitText("x "+strings.Repeat("a", 10), []MessageEntity{{Type: EntityTypeCode, Offset: 2, Length: 10}}, 8)
+	want = []TextPart{
+		{Text: "x"},
+		{Text: "aaaaaaaa", Entities: []MessageEntity{{Type: EntityTypeCode, Offset: 0, Length: 8}}},
+		{Text: "aa", Entities: []MessageEntity{{Type: EntityTypeCode, Offset: 0, Length: 2}}},
+	}
+
+	if !reflect.DeepEqual(parts, want) {
+		t.Errorf("SplitText() = %+v, want %+v", parts, want)
+	}
+}
+
+func TestTelegramClient_SendLongMessage(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"sendMessage": `{"ok":true,"result":{"message_id":1}}`,
+	})
+
+	paragraph := "*" + strings.Repeat("word ", 500) + "*"
+	responses, err := client.SendLongMessage(MessageRequest{
+		ChatId:      "@channel",
+		Text:        paragraph + "\n\n" + paragraph,
+		ParseMode:   TgParseModMarkdownV2,
+		ReplyMarkup: InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{{Text: "More", URL: "https://example.com"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := transport.calls("sendMessage")
+	if len(responses) != 2 || len(calls) != 2 {
+		t.Fatalf("got %d responses and %d calls, want 2", len(responses), len(calls))
+	}
+
+	for i, call := range calls {
+		var sent MessageRequest
+		if err := json.Unmarshal(call.Body, &sent); err != nil {
+			t.Fatal(err)
+		}
+
+		if sent.ParseMode != "" || len(sent.Entities) != 1 || sent.Entities[0].Type != EntityTypeBold {
+			t.Errorf("part %d: unexpected formatting: %s", i, call.Body)
+		}
+
+		if (sent.ReplyMarkup != nil) != (i == 1) {
+			t.Errorf("part %d: reply_markup = %v", i, sent.ReplyMarkup)
+		}
+	}
+}
+
+func TestTelegramClient_SendPhotoWithLongCaption(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"sendPhoto":   `{"ok":true,"result":{"message_id":1}}`,
+		"sendMessage": `{"ok":true,"result":{"message_id":2}}`,
+	})
+
+	caption := strings.Repeat("Sentence number one. ", 60)
+	responses, err := client.SendPhotoWithLongCaption(PhotoRequest{
+		ChatId:  "@channel",
+		Photo:   base64.StdEncoding.EncodeToString([]byte("jpeg")),
+		Caption: caption,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(responses) != 2 || responses[0].Result.MessageId != 1 || responses[1].Result.MessageId != 2 {
+		t.Fatalf("unexpected responses: %+v", responses)
+	}
+
+	form := readMultipartForm(t, transport.calls("sendPhoto")[0])
+	if UTF16Len(form[TgFieldCaption]) > MaxCaptionLength || !strings.HasSuffix(form[TgFieldCaption], ".") {
+		t.Errorf("caption of %d characters: %q", UTF16Len(form[TgFieldCaption]), form[TgFieldCaption])
+	}
+
+	var followUp MessageRequest
+	if err := json.Unmarshal(transport.calls("sendMessage")[0].Body, &followUp); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := form[TgFieldCaption] + " " + followUp.Text; got != caption {
+		t.Errorf("caption and follow-up do not add up to the original caption: %q + %q", form[TgFieldCaption], followUp.Text)
+	}
+}
@@ -0,0 +1,304 @@
+package teledau
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"math"
+)
+
+var (
+	ErrStickerFormat   = errors.New("sticker is not a PNG or WebP image")
+	ErrStickerSize     = errors.New("sticker must be 512 px on one side and at most 512 px on the other")
+	ErrStickerTooLarge = errors.New("sticker file is larger than 512 KB")
+	// ErrStickerSourceTooLarge is returned by PrepareSticker for images over MaxStickerSourcePixels.
+	ErrStickerSourceTooLarge = errors.New("image is too large to prepare as a sticker")
+)
+
+// PrepareSticker turns a PNG, JPEG or GIF image into a static sticker: the image is scaled
+// so that its longer side is StickerSize, keeping the aspect ratio, and encoded as StickerImagePNG
+// or StickerImageWebP. Only the first frame of an animated GIF is used, and images with more than
+// MaxStickerSourcePixels pixels are rejected before they are decoded.
+//
+// The result can be sent with SendStickerMessage as StickerFile, or passed to UploadStickerFile
+// and InputSticker with StickerFormatStatic.
+func PrepareSticker(data []byte, encoding string) (InputFile, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return InputFile{}, err
+	}
+
+	if config.Width*config.Height > MaxStickerSourcePixels {
+		return InputFile{}, fmt.Errorf("%w: %dx%d", ErrStickerSourceTooLarge, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return InputFile{}, err
+	}
+
+	return PrepareStickerImage(img, encoding)
+}
+
+// PrepareStickerImage is PrepareSticker for an image that is already decoded.
+func PrepareStickerImage(img image.Image, encoding string) (InputFile, error) {
+	resized := ResizeSticker(img)
+
+	var buf bytes.Buffer
+	var name string
+	switch encoding {
+	case StickerImagePNG:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, resized); err != nil {
+			return InputFile{}, err
+		}
+
+		name = TgStickerPNGFileName
+	case StickerImageWebP:
+		if err := EncodeWebP(&buf, resized); err != nil {
+			return InputFile{}, err
+		}
+
+		name = TgStickerFileName
+	default:
+		return InputFile{}, fmt.Errorf("unknown sticker encoding %q", encoding)
+	}
+
+	if err := ValidateSticker(buf.Bytes()); err != nil {
+		return InputFile{}, err
+	}
+
+	return InputFile{Name: name, Data: buf.Bytes()}, nil
+}
+
+// ValidateSticker checks that data is a PNG or WebP image the Bot API accepts as a static sticker:
+// StickerSize on one side, no larger on the other, and at most MaxStickerFileSize bytes.
+func ValidateSticker(data []byte) error {
+	if len(data) > MaxStickerFileSize {
+		return fmt.Errorf("%w: %d bytes", ErrStickerTooLarge, len(data))
+	}
+
+	var width, height int
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		config, err := png.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+
+		width, height = config.Width, config.Height
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		var err error
+		width, height, err = webpSize(data)
+		if err != nil {
+			return err
+		}
+	default:
+		return ErrStickerFormat
+	}
+
+	longer, shorter := width, height
+	if shorter > longer {
+		longer, shorter = shorter, longer
+	}
+
+	if longer != StickerSize || shorter < 1 {
+		return fmt.Errorf("%w: got %dx%d", ErrStickerSize, width, height)
+	}
+
+	return nil
+}
+
+// ResizeSticker scales img so that its longer side is StickerSize, keeping the aspect ratio.
+func ResizeSticker(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	width, height := StickerSize, StickerSize
+	if b.Dx() > b.Dy() {
+		height = int(math.Round(float64(b.Dy()) * StickerSize / float64(b.Dx())))
+	} else if b.Dy() > b.Dx() {
+		width = int(math.Round(float64(b.Dx()) * StickerSize / float64(b.Dy())))
+	}
+
+	if width < 1 {
+		width = 1
+	}
+
+	if height < 1 {
+		height = 1
+	}
+
+	return resizeImage(img, width, height)
+}
+
+// resizeImage scales img to width x height with a triangle filter, which averages all source
+// pixels when shrinking. Colors are filtered premultiplied, so transparent pixels don't bleed.
+// The source is read one row at a time, so memory use depends on the result only.
+func resizeImage(img image.Image, width, height int) *image.NRGBA {
+	b := img.Bounds()
+	srcWidth, srcHeight := b.Dx(), b.Dy()
+
+	xWeights := resizeWeights(srcWidth, width)
+
+	// targets lists the destination rows every source row contributes to.
+	type target struct {
+		row    int
+		weight float64
+	}
+
+	targets := make([][]target, srcHeight)
+	for y, weights := range resizeWeights(srcHeight, height) {
+		for _, w := range weights {
+			targets[w.index] = append(targets[w.index], target{row: y, weight: w.weight})
+		}
+	}
+
+	src := make([]float64, srcWidth*4)
+	row := make([]float64, width*4)
+	dst := make([]float64, width*height*4)
+
+	for y := 0; y < srcHeight; y++ {
+		if len(targets[y]) == 0 {
+			continue
+		}
+
+		readPremultipliedRow(img, b.Min.Y+y, src)
+
+		for x, weights := range xWeights {
+			var p [4]float64
+			for _, w := range weights {
+				i := w.index * 4
+				p[0] += src[i] * w.weight
+				p[1] += src[i+1] * w.weight
+				p[2] += src[i+2] * w.weight
+				p[3] += src[i+3] * w.weight
+			}
+
+			copy(row[x*4:], p[:])
+		}
+
+		for _, t := range targets[y] {
+			out := dst[t.row*width*4 : (t.row+1)*width*4]
+			for i, v := range row {
+				out[i] += v * t.weight
+			}
+		}
+	}
+
+	resized := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := (y*width + x) * 4
+			resized.SetNRGBA(x, y, unpremultiply([4]float64{dst[i], dst[i+1], dst[i+2], dst[i+3]}))
+		}
+	}
+
+	return resized
+}
+
+// readPremultipliedRow reads row y of img as premultiplied 16-bit RGBA into row.
+func readPremultipliedRow(img image.Image, y int, row []float64) {
+	b := img.Bounds()
+
+	switch src := img.(type) {
+	case *image.NRGBA:
+		pix := src.Pix[src.PixOffset(b.Min.X, y):]
+		for i := 0; i < len(row); i += 4 {
+			a := float64(pix[i+3])
+			row[i] = float64(pix[i]) * a * 0x101 / 0xff
+			row[i+1] = float64(pix[i+1]) * a * 0x101 / 0xff
+			row[i+2] = float64(pix[i+2]) * a * 0x101 / 0xff
+			row[i+3] = a * 0x101
+		}
+	case *image.RGBA:
+		pix := src.Pix[src.PixOffset(b.Min.X, y):]
+		for i := 0; i < len(row); i++ {
+			row[i] = float64(pix[i]) * 0x101
+		}
+	case *image.YCbCr:
+		for x := 0; x < len(row)/4; x++ {
+			yi, ci := src.YOffset(b.Min.X+x, y), src.COffset(b.Min.X+x, y)
+			r, g, bl := color.YCbCrToRGB(src.Y[yi], src.Cb[ci], src.Cr[ci])
+			row[x*4], row[x*4+1], row[x*4+2], row[x*4+3] = float64(r)*0x101, float64(g)*0x101, float64(bl)*0x101, 0xffff
+		}
+	default:
+		for x := 0; x < len(row)/4; x++ {
+			r, g, bl, a := img.At(b.Min.X+x, y).RGBA()
+			row[x*4], row[x*4+1], row[x*4+2], row[x*4+3] = float64(r), float64(g), float64(bl), float64(a)
+		}
+	}
+}
+
+type resizeWeight struct {
+	index  int
+	weight float64
+}
+
+// resizeWeights returns for every destination pixel the source pixels it is made of.
+func resizeWeights(srcSize, dstSize int) [][]resizeWeight {
+	scale := float64(srcSize) / float64(dstSize)
+	support := math.Max(scale, 1)
+
+	weights := make([][]resizeWeight, dstSize)
+	for i := range weights {
+		center := (float64(i)+0.5)*scale - 0.5
+		first := int(math.Floor(center - support))
+		last := int(math.Ceil(center + support))
+
+		sum := 0.0
+		for j := first; j <= last; j++ {
+			w := 1 - math.Abs(float64(j)-center)/support
+			if w <= 0 {
+				continue
+			}
+
+			index := j
+			if index < 0 {
+				index = 0
+			} else if index >= srcSize {
+				index = srcSize - 1
+			}
+
+			weights[i] = append(weights[i], resizeWeight{index: index, weight: w})
+			sum += w
+		}
+
+		for j := range weights[i] {
+			weights[i][j].weight /= sum
+		}
+	}
+
+	return weights
+}
+
+// unpremultiply converts a premultiplied 16-bit color to 8-bit NRGBA.
+func unpremultiply(p [4]float64) color.NRGBA {
+	a := p[3]
+	if a <= 0 {
+		return color.NRGBA{}
+	}
+
+	channel := func(v float64) uint8 {
+		v = math.Round(v)
+		if v < 0 {
+			return 0
+		}
+
+		if v > 0xff {
+			return 0xff
+		}
+
+		return uint8(v)
+	}
+
+	return color.NRGBA{
+		R: channel(p[0] * 0xff / a),
+		G: channel(p[1] * 0xff / a),
+		B: channel(p[2] * 0xff / a),
+		A: channel(a * 0xff / 0xffff),
+	}
+}
@@ -0,0 +1,226 @@
+package teledau
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+)
+
+func testStickerImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 255 / width), G: uint8(y * 255 / height), B: 0x80, A: 0xff})
+		}
+	}
+
+	return img
+}
+
+func TestPrepareSticker(t *testing.T) {
+	var pngData, jpegData, gifData bytes.Buffer
+	if err := png.Encode(&pngData, testStickerImage(800, 400)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := jpeg.Encode(&jpegData, testStickerImage(300, 600), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gif.Encode(&gifData, testStickerImage(100, 100), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		data          []byte
+		encoding      string
+		fileName      string
+		width, height int
+	}{
+		{name: "png to webp", data: pngData.Bytes(), encoding: StickerImageWebP, fileName: TgStickerFileName, width: 512, height: 256},
+		{name: "png to png", data: pngData.Bytes(), encoding: StickerImagePNG, fileName: TgStickerPNGFileName, width: 512, height: 256},
+		{name: "jpeg to webp", data: jpegData.Bytes(), encoding: StickerImageWebP, fileName: TgStickerFileName, width: 256, height: 512},
+		{name: "gif to png", data: gifData.Bytes(), encoding: StickerImagePNG, fileName: TgStickerPNGFileName, width: 512, height: 512},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := PrepareSticker(tt.data, tt.encoding)
+			if err != nil {
+				t.Fatalf("PrepareSticker returned error: %v", err)
+			}
+
+			if file.Name != tt.fileName || file.FileId != "" {
+				t.Fatalf("unexpected file %q, file_id %q", file.Name, file.FileId)
+			}
+
+			var decoded image.Image
+			if tt.encoding == StickerImageWebP {
+				decoded, err = decodeTestVP8L(file.Data)
+			} else {
+				decoded, err = png.Decode(bytes.NewReader(file.Data))
+			}
+			if err != nil {
+				t.Fatalf("decoding the sticker failed: %v", err)
+			}
+
+			if size := decoded.Bounds().Size(); size.X != tt.width || size.Y != tt.height {
+				t.Fatalf("sticker is %v, want %dx%d", size, tt.width, tt.height)
+			}
+
+			if err := ValidateSticker(file.Data); err != nil {
+				t.Fatalf("ValidateSticker returned error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrepareSticker_Errors(t *testing.T) {
+	if _, err := PrepareSticker([]byte("not an image"), StickerImageWebP); err == nil {
+		t.Fatal("expected an error for data that isn't an image")
+	}
+
+	if _, err := PrepareStickerImage(testStickerImage(10, 10), "bmp"); err == nil {
+		t.Fatal("expected an error for an unknown encoding")
+	}
+
+	// Noise doesn't compress below the size limit.
+	noise := image.NewNRGBA(image.Rect(0, 0, 512, 512))
+	rand.New(rand.NewSource(1)).Read(noise.Pix)
+
+	if _, err := PrepareStickerImage(noise, StickerImageWebP); !errors.Is(err, ErrStickerTooLarge) {
+		t.Fatalf("expected ErrStickerTooLarge, got %v", err)
+	}
+}
+
+func TestValidateSticker(t *testing.T) {
+	encode := func(width, height int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, width, height))); err != nil {
+			t.Fatal(err)
+		}
+
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "square", data: encode(512, 512)},
+		{name: "narrow", data: encode(100, 512)},
+		{name: "too small", data: encode(400, 300), err: ErrStickerSize},
+		{name: "too big", data: encode(600, 512), err: ErrStickerSize},
+		{name: "file too large", data: make([]byte, MaxStickerFileSize+1), err: ErrStickerTooLarge},
+		{name: "jpeg", data: []byte("\xff\xd8\xff\xe0"), err: ErrStickerFormat},
+	}
+
+	for _, tt := range tests {
+		err := ValidateSticker(tt.data)
+		if tt.err == nil && err != nil || tt.err != nil && !errors.Is(err, tt.err) {
+			t.Fatalf("%s: ValidateSticker = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestResizeSticker(t *testing.T) {
+	// Opaque red on the right, transparent blue on the left.
+	img := image.NewNRGBA(image.Rect(0, 0, 1000, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 1000; x++ {
+			if x < 500 {
+				img.SetNRGBA(x, y, color.NRGBA{B: 0xff})
+			} else {
+				img.SetNRGBA(x, y, color.NRGBA{R: 0xff, A: 0xff})
+			}
+		}
+	}
+
+	resized := ResizeSticker(img)
+	if size := resized.Bounds().Size(); size.X != 512 || size.Y != 5 {
+		t.Fatalf("resized to %v, want 512x5", size)
+	}
+
+	for x := 0; x < 512; x++ {
+		c := resized.NRGBAAt(x, 2)
+		if c.A > 0 && (c.R != 0xff || c.B != 0) {
+			t.Fatalf("transparent color bled into pixel %d: %v", x, c)
+		}
+
+		if x < 250 && c.A != 0 || x > 262 && c.A != 0xff {
+			t.Fatalf("pixel %d has alpha %d", x, c.A)
+		}
+	}
+
+	small := ResizeSticker(testStickerImage(64, 32))
+	if size := small.Bounds().Size(); size.X != 512 || size.Y != 256 {
+		t.Fatalf("enlarged to %v, want 512x256", size)
+	}
+}
+
+func TestPrepareSticker_SourceTooLarge(t *testing.T) {
+	// Only the header is read, so the pixel data may be missing.
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:], 10000)
+	binary.BigEndian.PutUint32(data[20:], 10000)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+
+	if _, err := PrepareSticker(data, StickerImageWebP); !errors.Is(err, ErrStickerSourceTooLarge) {
+		t.Fatalf("expected ErrStickerSourceTooLarge, got %v", err)
+	}
+}
+
+func TestResizeSticker_SourceTypes(t *testing.T) {
+	rgba := image.NewRGBA(image.Rect(10, 20, 710, 370))
+	ycbcr := image.NewYCbCr(image.Rect(0, 0, 700, 350), image.YCbCrSubsampleRatio420)
+	gray := image.NewGray16(image.Rect(0, 0, 700, 350))
+	for y := 0; y < 350; y++ {
+		for x := 0; x < 700; x++ {
+			rgba.SetRGBA(10+x, 20+y, color.RGBA{R: 0x40, G: 0x80, B: 0xc0, A: 0xff})
+			gray.SetGray16(x, y, color.Gray16{Y: 0x8080})
+		}
+	}
+
+	for i := range ycbcr.Y {
+		ycbcr.Y[i] = 0x80
+	}
+	for i := range ycbcr.Cb {
+		ycbcr.Cb[i], ycbcr.Cr[i] = 0x80, 0x80
+	}
+
+	tests := []struct {
+		name string
+		img  image.Image
+		want color.NRGBA
+	}{
+		{name: "rgba", img: rgba, want: color.NRGBA{R: 0x40, G: 0x80, B: 0xc0, A: 0xff}},
+		{name: "ycbcr", img: ycbcr, want: color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}},
+		{name: "gray16", img: gray, want: color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}},
+	}
+
+	for _, tt := range tests {
+		resized := ResizeSticker(tt.img)
+		if size := resized.Bounds().Size(); size.X != 512 || size.Y != 256 {
+			t.Fatalf("%s: resized to %v, want 512x256", tt.name, size)
+		}
+
+		if got := resized.NRGBAAt(300, 100); got != tt.want {
+			t.Errorf("%s: pixel = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
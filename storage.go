@@ -0,0 +1,430 @@
+package teledau
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrStorageNotFound is returned by Storage.Get when the key does not exist or has expired.
+var ErrStorageNotFound = errors.New("storage: key not found")
+
+// Storage is a key/value store for bot state such as sessions and counters.
+// A zero ttl means the value never expires. Get returns a non-nil slice for every
+// existing key, including one that holds an empty value.
+type Storage interface {
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(key string) error
+
+	// CompareAndSwap stores value only if the current value equals old.
+	// A nil old means the key must not exist. It reports whether the swap happened.
+	CompareAndSwap(key string, old, value []byte, ttl time.Duration) (bool, error)
+}
+
+type storageItem struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+func newStorageItem(value []byte, ttl time.Duration) storageItem {
+	item := storageItem{Value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		item.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	return item
+}
+
+func (i storageItem) expired(now time.Time) bool {
+	return !i.ExpiresAt.IsZero() && !now.Before(i.ExpiresAt)
+}
+
+// MemoryStorage keeps values in process memory. State is lost on restart.
+type MemoryStorage struct {
+	mu    sync.Mutex
+	items map[string]storageItem
+}
+
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{items: make(map[string]storageItem)}
+}
+
+func (s *MemoryStorage) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.lookup(key)
+	if !ok {
+		return nil, ErrStorageNotFound
+	}
+
+	return append([]byte{}, item.Value...), nil
+}
+
+func (s *MemoryStorage) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = newStorageItem(value, ttl)
+
+	return nil
+}
+
+func (s *MemoryStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.items, key)
+
+	return nil
+}
+
+func (s *MemoryStorage) CompareAndSwap(key string, old, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.lookup(key)
+	if !casMatches(ok, item.Value, old) {
+		return false, nil
+	}
+
+	s.items[key] = newStorageItem(value, ttl)
+
+	return true, nil
+}
+
+func (s *MemoryStorage) lookup(key string) (storageItem, bool) {
+	item, ok := s.items[key]
+	if !ok {
+		return storageItem{}, false
+	}
+
+	if item.expired(time.Now()) {
+		delete(s.items, key)
+
+		return storageItem{}, false
+	}
+
+	return item, true
+}
+
+// FileStorage keeps values in memory and persists them to a JSON file after every write,
+// so state survives restarts. It is meant for a single bot process.
+type FileStorage struct {
+	mu    sync.Mutex
+	path  string
+	items map[string]storageItem
+}
+
+// NewFileStorage opens the JSON file at path, creating it on the first write if it does not exist.
+func NewFileStorage(path string) (*FileStorage, error) {
+	s := &FileStorage{path: path, items: make(map[string]storageItem)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return s, nil
+	}
+
+	if err := json.Unmarshal(data, &s.items); err != nil {
+		return nil, fmt.Errorf("storage: decode %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+func (s *FileStorage) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if !ok || item.expired(time.Now()) {
+		return nil, ErrStorageNotFound
+	}
+
+	return append([]byte{}, item.Value...), nil
+}
+
+func (s *FileStorage) Set(key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = newStorageItem(value, ttl)
+
+	return s.flush()
+}
+
+func (s *FileStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.items[key]; !ok {
+		return nil
+	}
+
+	delete(s.items, key)
+
+	return s.flush()
+}
+
+func (s *FileStorage) CompareAndSwap(key string, old, value []byte, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.items[key]
+	if ok && item.expired(time.Now()) {
+		ok = false
+	}
+
+	if !casMatches(ok, item.Value, old) {
+		return false, nil
+	}
+
+	s.items[key] = newStorageItem(value, ttl)
+
+	return true, s.flush()
+}
+
+// flush drops expired items and atomically rewrites the backing file.
+func (s *FileStorage) flush() error {
+	now := time.Now()
+	for key, item := range s.items {
+		if item.expired(now) {
+			delete(s.items, key)
+		}
+	}
+
+	data, err := json.Marshal(s.items)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// SQLStorage keeps values in a database/sql table with the columns
+// (storage_key, storage_value, expires_at). The queries use "?" placeholders,
+// which works with SQLite and MySQL drivers.
+type SQLStorage struct {
+	Ctx   context.Context
+	DB    *sql.DB
+	Table string
+}
+
+// NewSQLStorage creates the table if it does not exist and returns a storage backed by it.
+func NewSQLStorage(ctx context.Context, db *sql.DB, table string) (*SQLStorage, error) {
+	s := &SQLStorage{Ctx: ctx, DB: db, Table: table}
+
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	storage_key VARCHAR(255) NOT NULL PRIMARY KEY,
+	storage_value BLOB NOT NULL,
+	expires_at BIGINT NOT NULL DEFAULT 0
+)`, table)
+
+	if _, err := db.ExecContext(ctx, query); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *SQLStorage) Get(key string) ([]byte, error) {
+	value, ok, err := s.get(s.DB, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, ErrStorageNotFound
+	}
+
+	return value, nil
+}
+
+func (s *SQLStorage) Set(key string, value []byte, ttl time.Duration) error {
+	tx, err := s.DB.BeginTx(s.Ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := s.put(tx, key, value, ttl); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLStorage) Delete(key string) error {
+	_, err := s.DB.ExecContext(s.Ctx, fmt.Sprintf("DELETE FROM %s WHERE storage_key = ?", s.Table), key)
+
+	return err
+}
+
+// CompareAndSwap is a single conditional UPDATE, or an INSERT when old is nil, so it is atomic
+// without locking and a lost race reports false instead of an error.
+func (s *SQLStorage) CompareAndSwap(key string, old, value []byte, ttl time.Duration) (bool, error) {
+	if value == nil {
+		value = []byte{}
+	}
+
+	now := time.Now().UnixNano()
+
+	if old == nil {
+		if _, err := s.DB.ExecContext(s.Ctx, fmt.Sprintf("DELETE FROM %s WHERE storage_key = ? AND expires_at > 0 AND expires_at <= ?", s.Table), key, now); err != nil {
+			return false, err
+		}
+
+		_, err := s.DB.ExecContext(s.Ctx, fmt.Sprintf("INSERT INTO %s (storage_key, storage_value, expires_at) VALUES (?, ?, ?)", s.Table), key, value, sqlExpiresAt(ttl))
+		if err == nil {
+			return true, nil
+		}
+
+		// The insert fails on the primary key when the key exists; drivers report that differently.
+		if _, exists, getErr := s.get(s.DB, key); getErr == nil && exists {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	result, err := s.DB.ExecContext(s.Ctx, fmt.Sprintf(
+		"UPDATE %s SET storage_value = ?, expires_at = ? WHERE storage_key = ? AND storage_value = ? AND (expires_at = 0 OR expires_at > ?)", s.Table),
+		value, sqlExpiresAt(ttl), key, old, now)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if n > 0 {
+		return true, nil
+	}
+
+	// MySQL counts changed rows only, so swapping a value for itself affects none.
+	if bytes.Equal(old, value) {
+		current, exists, err := s.get(s.DB, key)
+
+		return err == nil && exists && bytes.Equal(current, old), err
+	}
+
+	return false, nil
+}
+
+type sqlQueryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (s *SQLStorage) get(q sqlQueryer, key string) ([]byte, bool, error) {
+	var (
+		value     []byte
+		expiresAt int64
+	)
+
+	row := q.QueryRowContext(s.Ctx, fmt.Sprintf("SELECT storage_value, expires_at FROM %s WHERE storage_key = ?", s.Table), key)
+	err := row.Scan(&value, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	if expiresAt > 0 && time.Now().UnixNano() >= expiresAt {
+		return nil, false, nil
+	}
+
+	return value, true, nil
+}
+
+func (s *SQLStorage) put(tx *sql.Tx, key string, value []byte, ttl time.Duration) error {
+	if _, err := tx.ExecContext(s.Ctx, fmt.Sprintf("DELETE FROM %s WHERE storage_key = ?", s.Table), key); err != nil {
+		return err
+	}
+
+	if value == nil {
+		value = []byte{}
+	}
+
+	_, err := tx.ExecContext(s.Ctx, fmt.Sprintf("INSERT INTO %s (storage_key, storage_value, expires_at) VALUES (?, ?, ?)", s.Table), key, value, sqlExpiresAt(ttl))
+
+	return err
+}
+
+func sqlExpiresAt(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+
+	return time.Now().Add(ttl).UnixNano()
+}
+
+func casMatches(exists bool, current, old []byte) bool {
+	if old == nil {
+		return !exists
+	}
+
+	return exists && bytes.Equal(current, old)
+}
+
+// UpdateStorage replaces the value at key with the result of update, using CompareAndSwap
+// and retrying when another writer changed the value in between. update receives nil
+// when the key does not exist, and a non-nil empty slice for an empty value; it may be
+// called several times.
+func UpdateStorage(s Storage, key string, ttl time.Duration, update func(current []byte) ([]byte, error)) error {
+	for {
+		current, err := s.Get(key)
+		if errors.Is(err, ErrStorageNotFound) {
+			current = nil
+		} else if err != nil {
+			return err
+		}
+
+		value, err := update(current)
+		if err != nil {
+			return err
+		}
+
+		swapped, err := s.CompareAndSwap(key, current, value, ttl)
+		if err != nil {
+			return err
+		}
+
+		if swapped {
+			return nil
+		}
+	}
+}
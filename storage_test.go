@@ -0,0 +1,231 @@
+package teledau
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func testStorage(t *testing.T, s Storage) {
+	if _, err := s.Get("missing"); !errors.Is(err, ErrStorageNotFound) {
+		t.Fatalf("Get missing: got %v, want ErrStorageNotFound", err)
+	}
+
+	if err := s.Set("a", []byte("1"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := s.Get("a")
+	if err != nil || string(value) != "1" {
+		t.Fatalf("Get a: got %q, %v", value, err)
+	}
+
+	ok, err := s.CompareAndSwap("a", []byte("2"), []byte("3"), 0)
+	if err != nil || ok {
+		t.Fatalf("CompareAndSwap with stale value: got %v, %v", ok, err)
+	}
+
+	ok, err = s.CompareAndSwap("a", []byte("1"), []byte("2"), 0)
+	if err != nil || !ok {
+		t.Fatalf("CompareAndSwap with current value: got %v, %v", ok, err)
+	}
+
+	ok, err = s.CompareAndSwap("b", nil, []byte("new"), 0)
+	if err != nil || !ok {
+		t.Fatalf("CompareAndSwap on missing key: got %v, %v", ok, err)
+	}
+
+	ok, err = s.CompareAndSwap("b", nil, []byte("again"), 0)
+	if err != nil || ok {
+		t.Fatalf("CompareAndSwap on existing key with nil old: got %v, %v", ok, err)
+	}
+
+	if err := s.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Get("a"); !errors.Is(err, ErrStorageNotFound) {
+		t.Fatalf("Get deleted: got %v, want ErrStorageNotFound", err)
+	}
+
+	if err := s.Set("ttl", []byte("x"), 20*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := s.Get("ttl"); !errors.Is(err, ErrStorageNotFound) {
+		t.Fatalf("Get expired: got %v, want ErrStorageNotFound", err)
+	}
+
+	ok, err = s.CompareAndSwap("ttl", nil, []byte("y"), 0)
+	if err != nil || !ok {
+		t.Fatalf("CompareAndSwap on expired key: got %v, %v", ok, err)
+	}
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, NewMemoryStorage())
+}
+
+func TestFileStorage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	s, err := NewFileStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStorage(t, s)
+
+	reopened, err := NewFileStorage(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := reopened.Get("b")
+	if err != nil || string(value) != "new" {
+		t.Fatalf("Get after reopen: got %q, %v", value, err)
+	}
+}
+
+func TestSQLStorage(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	s, err := NewSQLStorage(context.Background(), db, "bot_state")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testStorage(t, s)
+}
+
+func TestUpdateStorage(t *testing.T) {
+	s := NewMemoryStorage()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := UpdateStorage(s, "counter", 0, func(current []byte) ([]byte, error) {
+				n, _ := strconv.Atoi(string(current))
+
+				return []byte(strconv.Itoa(n + 1)), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if value, _ := s.Get("counter"); string(value) != "20" {
+		t.Errorf("counter = %s, want 20", value)
+	}
+}
+
+func TestUpdateStorage_EmptyValue(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "state.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	sqlStorage, err := NewSQLStorage(context.Background(), db, "bot_state")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileStorage, err := NewFileStorage(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, s := range map[string]Storage{"memory": NewMemoryStorage(), "file": fileStorage, "sql": sqlStorage} {
+		if err := s.Set("empty", nil, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		if value, err := s.Get("empty"); err != nil || value == nil || len(value) != 0 {
+			t.Fatalf("%s: Get = %q, %v, want a non-nil empty value", name, value, err)
+		}
+
+		calls := 0
+		err := UpdateStorage(s, "empty", 0, func(current []byte) ([]byte, error) {
+			calls++
+			if current == nil || len(current) != 0 {
+				t.Fatalf("%s: update got %q, want an empty value", name, current)
+			}
+
+			return nil, nil
+		})
+		if err != nil || calls != 1 {
+			t.Fatalf("%s: UpdateStorage = %v after %d calls", name, err, calls)
+		}
+
+		// The value is empty again after the update returned nil.
+		err = UpdateStorage(s, "empty", 0, func(current []byte) ([]byte, error) {
+			return []byte("1"), nil
+		})
+		if value, _ := s.Get("empty"); err != nil || string(value) != "1" {
+			t.Fatalf("%s: second UpdateStorage = %q, %v", name, value, err)
+		}
+	}
+}
+
+func TestSQLStorage_ConcurrentUpdates(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "state.db")+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	s, err := NewSQLStorage(context.Background(), db, "bot_state")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 5; j++ {
+				err := UpdateStorage(s, "counter", 0, func(current []byte) ([]byte, error) {
+					n, _ := strconv.Atoi(string(current))
+
+					return []byte(strconv.Itoa(n + 1)), nil
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if value, _ := s.Get("counter"); string(value) != "50" {
+		t.Errorf("counter = %s, want 50", value)
+	}
+
+	ok, err := s.CompareAndSwap("counter", []byte("50"), []byte("50"), 0)
+	if err != nil || !ok {
+		t.Errorf("CompareAndSwap to the same value: got %v, %v", ok, err)
+	}
+}
@@ -12,22 +12,66 @@ import (
 	"log"
 	"mime/multipart"
 	"net/http"
+	neturl "net/url"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type Telegram interface {
 	GetChat(chatID string) (*GetChatResponse, error)
+	SetChatTitle(chatId, title string) error
+	SetChatDescription(chatId, description string) error
+	SetChatPhoto(chatId, photo string) error
+	DeleteChatPhoto(chatId string) error
+	PinChatMessage(chatId string, messageId int, disableNotification bool) error
+	UnpinChatMessage(chatId string, messageId int) error
+	UnpinAllChatMessages(chatId string) error
+	LeaveChat(chatId string) error
 
 	SendMessage(message MessageRequest) (SendMessageResponse, error)
 	EditMessage(message EditMessageRequest) (SendMessageResponse, error)
+	SendLongMessage(message MessageRequest) ([]SendMessageResponse, error)
 
 	SendMedia(chatId, media, message string, parseMode string) (*SendMessageResponse, error)
-	SendMediaGroups(chatId string, media []string, message, parseMode string) (*SendMessageResponse, error)
+	SendMediaGroup(chatId string, media []string, message, parseMode string) (*MediaPostResponse, error)
+	SendPhoto(photo PhotoRequest) (*SendMessageResponse, error)
+	SendPhotoGroup(group MediaGroupRequest) (*MediaPostResponse, error)
+	SendPhotoWithLongCaption(photo PhotoRequest) ([]SendMessageResponse, error)
+	SendPhotoGroupWithLongCaption(group MediaGroupRequest) (*MediaPostResponse, []SendMessageResponse, error)
 	EditCaption(message EditCaptionRequest) (SendMessageResponse, error)
+	EditMessageMedia(edit EditMessageMediaRequest) (SendMessageResponse, error)
+	EditMessageReplyMarkup(edit EditMessageReplyMarkupRequest) (SendMessageResponse, error)
+	EditMessageLiveLocation(edit EditMessageLiveLocationRequest) (SendMessageResponse, error)
+	StopMessageLiveLocation(chatId string, messageId int, replyMarkup interface{}) (SendMessageResponse, error)
+	StopPoll(chatId string, messageId int, replyMarkup interface{}) (*Poll, error)
+	EditInlineMessage(message EditMessageRequest) error
+	EditInlineCaption(message EditCaptionRequest) error
+	EditInlineMessageMedia(edit EditMessageMediaRequest) error
+	EditInlineMessageReplyMarkup(edit EditMessageReplyMarkupRequest) error
+	EditInlineMessageLiveLocation(edit EditMessageLiveLocationRequest) error
+	StopInlineMessageLiveLocation(inlineMessageId string, replyMarkup interface{}) error
+	DeleteMessage(chatId string, messageId int) error
+	DeleteMessages(chatId string, messageIds []int) error
+	ForwardMessage(forward ForwardMessageRequest) (SendMessageResponse, error)
+	ForwardMessages(forward ForwardMessagesRequest) ([]MessageId, error)
+	CopyMessage(message CopyMessageRequest) (*MessageId, error)
+	CopyMessages(messages CopyMessagesRequest) ([]MessageId, error)
 
 	SendSticker(chatId string, media string) (StikerResponse, error)
+	SendStickerMessage(sticker StickerRequest) (StikerResponse, error)
+	GetStickerSet(name string) (*StickerSet, error)
+	GetCustomEmojiStickers(customEmojiIds []string) ([]Sticker, error)
+	UploadStickerFile(userId int64, sticker InputFile, format string) (*File, error)
+	CreateNewStickerSet(set CreateNewStickerSetRequest) error
+	AddStickerToSet(userId int64, name string, sticker InputSticker) error
+	ReplaceStickerInSet(userId int64, name, oldSticker string, sticker InputSticker) error
+	SetStickerPositionInSet(sticker string, position int) error
+	DeleteStickerFromSet(sticker string) error
+	SetStickerEmojiList(sticker string, emojiList []string) error
+	SetStickerSetThumbnail(name string, userId int64, thumbnail InputFile, format string) error
+	SetCustomEmojiStickerSetThumbnail(name, customEmojiId string) error
 
 	GetFilePath(fileID string) (string, error)
 	DownloadByte(filePath string) ([]byte, error)
@@ -35,10 +79,48 @@ type Telegram interface {
 	DownloadStrBase64(filePath string) (string, error)
 
 	GenerateInviteLinks(invite CreateChatInviteLinkRequest) (*InviteLinks, error)
+	EditChatInviteLink(invite EditChatInviteLinkRequest) (*InviteLink, error)
+	CreateChatSubscriptionInviteLink(invite CreateChatSubscriptionInviteLinkRequest) (*InviteLink, error)
+	EditChatSubscriptionInviteLink(chatId, inviteLink, name string) (*InviteLink, error)
+	RevokeChatInviteLink(chatId, inviteLink string) (*InviteLink, error)
+	ExportChatInviteLink(chatId string) (string, error)
+	ApproveChatJoinRequest(chatId string, userId int64) error
+	DeclineChatJoinRequest(chatId string, userId int64) error
+	AnswerChatJoinRequest(request ChatJoinRequest, approve bool) error
 
 	SendPoll(poolRequest PollRequest) (PollResponse, error)
+
+	AnswerInlineQuery(answer AnswerInlineQueryRequest) error
+
+	CreateForumTopic(topic CreateForumTopicRequest) (*ForumTopic, error)
+	EditForumTopic(topic EditForumTopicRequest) error
+	CloseForumTopic(chatId string, messageThreadId int) error
+	ReopenForumTopic(chatId string, messageThreadId int) error
+	DeleteForumTopic(chatId string, messageThreadId int) error
+	UnpinAllForumTopicMessages(chatId string, messageThreadId int) error
+	EditGeneralForumTopic(chatId, name string) error
+	CloseGeneralForumTopic(chatId string) error
+	ReopenGeneralForumTopic(chatId string) error
+	HideGeneralForumTopic(chatId string) error
+	UnhideGeneralForumTopic(chatId string) error
+	UnpinAllGeneralForumTopicMessages(chatId string) error
+	GetForumTopicIconStickers() ([]Sticker, error)
+
+	BanChatMember(ban BanChatMemberRequest) error
+	UnbanChatMember(chatId string, userId int64, onlyIfBanned bool) error
+	RestrictChatMember(restrict RestrictChatMemberRequest) error
+	PromoteChatMember(promote PromoteChatMemberRequest) error
+	SetChatPermissions(permissions SetChatPermissionsRequest) error
+	SetChatAdministratorCustomTitle(chatId string, userId int64, customTitle string) error
+	BanChatSenderChat(chatId string, senderChatId int64) error
+	UnbanChatSenderChat(chatId string, senderChatId int64) error
+	GetChatAdministrators(chatId string) ([]NewChatMember, error)
+	GetChatMember(chatId string, userId int64) (*NewChatMember, error)
+	GetChatMemberCount(chatId string) (int, error)
 }
 
+var _ Telegram = (*TelegramClient)(nil)
+
 type TelegramClient struct {
 	Ctx        context.Context
 	BotToken   string
@@ -77,7 +159,7 @@ func NewTelegramClientWithClient(ctx context.Context, botToken string, httpClien
 // processing, it logs the error and returns an empty GetChatResponse along with the error.
 func (t *TelegramClient) GetChat(chatID string) (*GetChatResponse, error) {
 
-	url := TgBotBaseUrl + t.BotToken + TgBotGetChat + chatID
+	url := TgBotBaseUrl + t.BotToken + TgBotGetChat + neturl.QueryEscape(chatID)
 
 	req, err := http.NewRequestWithContext(t.Ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -87,698 +169,524 @@ func (t *TelegramClient) GetChat(chatID string) (*GetChatResponse, error) {
 	}
 
 	req.Header.Set(HeaderContentType, ApplicationJson)
-	resp, err := t.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending request: %v", err)
-
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
-
-		return nil, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API request failed with status code: %d body %v", resp.StatusCode, string(bodyBytes))
-
-		return nil, err
-	}
 
 	var chat GetChatResponse
-	err = json.Unmarshal(bodyBytes, &chat)
-	if err != nil {
-		log.Printf("Error unmarshalling response: %v", err)
-
+	if err := t.do(req, &chat); err != nil {
 		return nil, err
 	}
 
 	return &chat, nil
 }
 func (t *TelegramClient) SendMessage(message MessageRequest) (SendMessageResponse, error) {
-	url := TgBotBaseUrl + t.BotToken + TgBotSendMessageUrl
-
-	messageData, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Error marshalling message: %v", err)
-
-		return SendMessageResponse{}, err
+	if message.DisableWebPagePreview && message.LinkPreviewOptions == nil {
+		message.LinkPreviewOptions = &LinkPreviewOptions{IsDisabled: true}
 	}
+	message.DisableWebPagePreview = false
 
-	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, url, bytes.NewBuffer(messageData))
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	req.Header.Set(HeaderContentType, ApplicationJson)
-	resp, err := t.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending request: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	defer resp.Body.Close()
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API request failed with status code: %d body %v", resp.StatusCode, string(bodyBytes))
-
-		return SendMessageResponse{}, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
-	}
-
-	var createdApplicant SendMessageResponse
-	if err := json.Unmarshal(bodyBytes, &createdApplicant); err != nil {
-		log.Printf("Error unmarshalling response body: %v", err)
-
+	var response SendMessageResponse
+	if err := t.postJSON(TgBotSendMessageUrl, message, &response); err != nil {
 		return SendMessageResponse{}, err
 	}
 
-	return createdApplicant, nil
+	return response, nil
 }
 func (t *TelegramClient) EditMessage(message EditMessageRequest) (SendMessageResponse, error) {
-	url := TgBotBaseUrl + t.BotToken + TgBotEditMessageUrl
-
-	messageData, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Error marshalling message: %v", err)
-
+	var response SendMessageResponse
+	if err := t.postJSON(TgBotEditMessageUrl, message, &response); err != nil {
 		return SendMessageResponse{}, err
 	}
 
-	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, url, bytes.NewBuffer(messageData))
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	req.Header.Set(HeaderContentType, ApplicationJson)
-	resp, err := t.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending request: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	defer resp.Body.Close()
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API request failed with status code: %d body %v", resp.StatusCode, string(bodyBytes))
-
-		return SendMessageResponse{}, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
-	}
-
-	var createdApplicant SendMessageResponse
-	if err := json.Unmarshal(bodyBytes, &createdApplicant); err != nil {
-		log.Printf("Error unmarshalling response body: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	return createdApplicant, nil
+	return response, nil
 }
 func (t *TelegramClient) EditCaption(message EditCaptionRequest) (SendMessageResponse, error) {
-	url := TgBotBaseUrl + t.BotToken + TgBotEditCaptionUrl
-
-	messageData, err := json.Marshal(message)
-	if err != nil {
-		log.Printf("Error marshalling message: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, url, bytes.NewBuffer(messageData))
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	req.Header.Set(HeaderContentType, ApplicationJson)
-	resp, err := t.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending request: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	defer resp.Body.Close()
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
-
-		return SendMessageResponse{}, err
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API request failed with status code: %d body %v", resp.StatusCode, string(bodyBytes))
-
-		return SendMessageResponse{}, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
-	}
-
-	var createdApplicant SendMessageResponse
-	if err := json.Unmarshal(bodyBytes, &createdApplicant); err != nil {
-		log.Printf("Error unmarshalling response body: %v", err)
-
+	var response SendMessageResponse
+	if err := t.postJSON(TgBotEditCaptionUrl, message, &response); err != nil {
 		return SendMessageResponse{}, err
 	}
 
-	return createdApplicant, nil
+	return response, nil
 }
 func (t *TelegramClient) SendPoll(poolRequest PollRequest) (PollResponse, error) {
-	url := TgBotBaseUrl + t.BotToken + TgBotSendPoolUrl
-
-	pollData, err := json.Marshal(poolRequest)
-	if err != nil {
-		log.Printf("Error marshalling message: %v", err)
-
-		return PollResponse{}, err
-	}
-
-	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, url, bytes.NewBuffer(pollData))
-	if err != nil {
-		log.Printf("Error creating request: %v", err)
+	var pollResponse PollResponse
+	err := t.postJSON(TgBotSendPoolUrl, poolRequest, &pollResponse)
 
-		return PollResponse{}, err
+	return pollResponse, err
+}
+func (t *TelegramClient) SendMedia(chatId string, media, message, parseMode string) (*SendMessageResponse, error) {
+	if len(parseMode) <= 0 {
+		parseMode = TgParseModMarkdownV2
 	}
 
-	req.Header.Set(HeaderContentType, ApplicationJson)
-	resp, err := t.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending request: %v", err)
+	return t.SendPhoto(PhotoRequest{
+		ChatId:    chatId,
+		Photo:     media,
+		Caption:   message,
+		ParseMode: parseMode,
+	})
+}
 
-		return PollResponse{}, err
-	}
+// SendPhoto uploads a base64-encoded photo with an optional caption.
+// When CaptionEntities is set, ParseMode is not sent so the caption needs no escaping.
+func (t *TelegramClient) SendPhoto(photo PhotoRequest) (*SendMessageResponse, error) {
+	response := new(SendMessageResponse)
 
-	defer resp.Body.Close()
-	bodyBytes, err := io.ReadAll(resp.Body)
+	imgData, err := base64.StdEncoding.DecodeString(photo.Photo)
 	if err != nil {
-		log.Printf("Error reading response body: %v", err)
+		log.Printf("Error decoding base64 string: %v", err)
 
-		return PollResponse{}, err
+		return response, err
 	}
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API request failed with status code: %d body %v", resp.StatusCode, string(bodyBytes))
 
-		return PollResponse{}, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
+	fields := map[string]string{
+		TgFieldChatId:  photo.ChatId,
+		TgFieldCaption: photo.Caption,
 	}
 
-	var pollResponse PollResponse
-	if err := json.Unmarshal(bodyBytes, &pollResponse); err != nil {
-		log.Printf("Error unmarshalling response body: %v", err)
+	if len(photo.CaptionEntities) > 0 {
+		entities, err := json.Marshal(photo.CaptionEntities)
+		if err != nil {
+			return response, err
+		}
 
-		return PollResponse{}, err
+		fields[TgFieldCaptionEntities] = string(entities)
+	} else if len(photo.ParseMode) > 0 {
+		fields[TgFieldNameParseMod] = photo.ParseMode
 	}
 
-	return pollResponse, nil
-}
-func (t *TelegramClient) SendMedia(chatId string, media, message, parseMode string) (*SendMessageResponse, error) {
-	imgData, err := base64.StdEncoding.DecodeString(media)
-	response := new(SendMessageResponse)
-	if err != nil {
-		fmt.Println("Error decoding base64 string:", err)
-
-		return response, err
+	options := sendOptions{
+		MessageThreadId:     photo.MessageThreadId,
+		DisableNotification: photo.DisableNotification,
+		ProtectContent:      photo.ProtectContent,
+		MessageEffectId:     photo.MessageEffectId,
+		ReplyParameters:     photo.ReplyParameters,
 	}
-	tempFile, err := ioutil.TempFile("", TempFileName)
-	if err != nil {
-		fmt.Println("Error creating temporary file:", err)
-
+	if err := options.writeFields(fields); err != nil {
 		return response, err
 	}
-	defer os.Remove(tempFile.Name()) // Clean up temporary file
-
-	if _, err := tempFile.Write(imgData); err != nil {
-		fmt.Println("Error writing image data to file:", err)
 
+	files := []multipartFile{{Field: TgFieldMediaType, Name: TgPhotoFileName, Data: imgData}}
+	if err := t.postMultipart(TgBotSendPhotoUrl, fields, files, response); err != nil {
 		return response, err
 	}
 
-	if err := tempFile.Close(); err != nil {
-		fmt.Println("Error closing temporary file:", err)
+	return response, nil
+}
 
-		return response, err
+func (t *TelegramClient) SendMediaGroup(chatId string, media []string, message, parseMode string) (*MediaPostResponse, error) {
+	if len(parseMode) <= 0 {
+		parseMode = TgParseModMarkdownV2
 	}
 
-	// Open the temporary file
-	file, err := os.Open(tempFile.Name())
-	if err != nil {
-		fmt.Println("Error opening temporary file:", err)
+	return t.SendPhotoGroup(MediaGroupRequest{
+		ChatId:    chatId,
+		Photos:    media,
+		Caption:   message,
+		ParseMode: parseMode,
+	})
+}
 
-		return response, err
-	}
-	defer file.Close()
+// SendPhotoGroup uploads base64-encoded photos as an album. The caption is attached to the first photo.
+func (t *TelegramClient) SendPhotoGroup(group MediaGroupRequest) (*MediaPostResponse, error) {
+	response := new(MediaPostResponse)
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+	var (
+		mediaGroups []MediaGroup
+		files       []multipartFile
+	)
 
-	part, err := writer.CreateFormFile(TgFieldMediaType, TempFileName)
-	if err != nil {
-		fmt.Println("Error creating form file:", err)
+	for i, s := range group.Photos {
+		imgData, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			log.Printf("Error decoding base64 string: %v", err)
 
-		return response, err
-	}
+			return response, err
+		}
 
-	if _, err := io.Copy(part, file); err != nil {
-		fmt.Println("Error copying file data:", err)
+		field := fmt.Sprintf("photo%d", i)
+		files = append(files, multipartFile{Field: field, Name: fmt.Sprintf(TgPhotoGroupFileNameFmt, i), Data: imgData})
 
-		return response, err
-	}
+		mediaG := MediaGroup{Type: TgFieldMediaType, Media: "attach://" + field}
+		if i == 0 {
+			mediaG.Caption = group.Caption
+			if len(group.CaptionEntities) > 0 {
+				mediaG.CaptionEntities = group.CaptionEntities
+			} else {
+				mediaG.ParseMode = group.ParseMode
+			}
+		}
 
-	writer.WriteField(TgFieldCaption, message)
-	if len(parseMode) <= 0 {
-		writer.WriteField(TgFieldNameParseMod, TgParseModMarkdownV2)
-	} else {
-		writer.WriteField(TgFieldNameParseMod, parseMode)
+		mediaGroups = append(mediaGroups, mediaG)
 	}
 
-	// Close the multipart writer
-	writer.Close()
-
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(TgBotSendPhotoUrlSptf, t.BotToken, chatId), nil)
+	mediaGroupBytes, err := json.Marshal(mediaGroups)
 	if err != nil {
-		fmt.Println("Error creating HTTP request:", err)
+		log.Printf("Error marshalling mediaGroups: %v", err)
 
 		return response, err
 	}
 
-	// Set the request body
-	req.Header.Set(HeaderContentType, writer.FormDataContentType())
-	req.Body = ioutil.NopCloser(body)
-
-	// Send the HTTP request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Println("Error sending HTTP request:", err)
-		return response, err
+	fields := map[string]string{
+		TgFieldChatId: group.ChatId,
+		TgFieldMedia:  string(mediaGroupBytes),
 	}
-	defer resp.Body.Close()
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
 
+	options := sendOptions{
+		MessageThreadId:     group.MessageThreadId,
+		DisableNotification: group.DisableNotification,
+		ProtectContent:      group.ProtectContent,
+		MessageEffectId:     group.MessageEffectId,
+		ReplyParameters:     group.ReplyParameters,
+	}
+	if err := options.writeFields(fields); err != nil {
 		return response, err
 	}
 
-	err = json.Unmarshal(bodyBytes, &response)
-	if err != nil {
-		log.Printf("Error unmarshal response body: %v", err)
-
+	if err := t.postMultipart(TgBotSendMediaGroupUrl, fields, files, response); err != nil {
 		return response, err
 	}
 
 	return response, nil
 }
+func (t *TelegramClient) SendSticker(chatId string, media string) (StikerResponse, error) {
+	return t.SendStickerMessage(StickerRequest{ChatId: chatId, Sticker: media})
+}
 
-func (t *TelegramClient) SendMediaGroup(chatId string, media []string, message, parseMode string) (*SendMessageResponse, error) {
-	prefix := time.Now().UnixMilli()
-	response := new(SendMessageResponse)
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	writer.WriteField(TgFieldChatId, chatId)
-
-	var mediaGroups []MediaGroup
-
-	for i, s := range media {
-		imgData, err := base64.StdEncoding.DecodeString(s)
-
-		if err != nil {
-			fmt.Println("Error decoding base64 string:", err)
-			return response, err
-		}
+// SendStickerMessage uploads a base64-encoded WEBP sticker.
+func (t *TelegramClient) SendStickerMessage(sticker StickerRequest) (StikerResponse, error) {
+	var response StikerResponse
 
-		tempFile, err := ioutil.TempFile("", fmt.Sprintf(TempFileNameFmt, prefix, i))
+	file := sticker.StickerFile
+	if file.FileId == "" && file.Data == nil {
+		imageBytes, err := base64.StdEncoding.DecodeString(sticker.Sticker)
 		if err != nil {
-			fmt.Println("Error creating temporary file:", err)
-			return response, err
-		}
+			log.Printf("Error decoding base64 string: %v", err)
 
-		defer os.Remove(tempFile.Name()) // Clean up temporary file
-
-		if _, err := tempFile.Write(imgData); err != nil {
-			fmt.Println("Error writing image data to file:", err)
-			return response, err
+			return StikerResponse{}, err
 		}
 
-		if err := tempFile.Close(); err != nil {
-			fmt.Println("Error closing temporary file:", err)
-			return response, err
-		}
+		file = InputFile{Data: imageBytes}
+	}
 
-		file, err := os.Open(tempFile.Name())
-		if err != nil {
-			fmt.Println("Error opening temporary file:", err)
-			return response, err
-		}
+	fields := map[string]string{TgFieldChatId: sticker.ChatId}
+	if len(sticker.Emoji) > 0 {
+		fields[TgFieldEmoji] = sticker.Emoji
+	}
 
-		defer file.Close()
+	options := sendOptions{
+		MessageThreadId:     sticker.MessageThreadId,
+		DisableNotification: sticker.DisableNotification,
+		ProtectContent:      sticker.ProtectContent,
+		MessageEffectId:     sticker.MessageEffectId,
+		ReplyParameters:     sticker.ReplyParameters,
+		ReplyMarkup:         sticker.ReplyMarkup,
+	}
+	if err := options.writeFields(fields); err != nil {
+		return StikerResponse{}, err
+	}
 
-		part, err := writer.CreateFormFile(fmt.Sprintf("photo%d", i), fmt.Sprintf(TempFileNameFmt, prefix, i))
-		if err != nil {
-			fmt.Println("Error creating form file:", err)
-			return response, err
-		}
+	files := file.writeField(fields, TgFieldSticker, TgStickerFileName)
+	if err := t.postMultipart(TgBotSendStickerUrl, fields, files, &response); err != nil {
+		return StikerResponse{}, err
+	}
 
-		if _, err := io.Copy(part, file); err != nil {
-			fmt.Println("Error copying file data:", err)
-			return response, err
-		}
+	return response, nil
+}
 
-		mediaG := MediaGroup{Type: TgFieldMediaType, Media: fmt.Sprintf("attach://photo%d", i)}
-		if i == 0 {
-			mediaG.Caption = message
+// GenerateInviteLinks creates an additional invite link; the bot must be an administrator with can_invite_users.
+func (t *TelegramClient) GenerateInviteLinks(invite CreateChatInviteLinkRequest) (*InviteLinks, error) {
+	inviteLinks := new(InviteLinks)
+	if err := t.postJSON(TgBotCreateInviteLinkUrl, invite, inviteLinks); err != nil {
+		return nil, err
+	}
 
-			if len(parseMode) <= 0 {
-				err := writer.WriteField(TgFieldNameParseMod, TgParseModMarkdownV2)
-				if err != nil {
-					return nil, err
-				}
-				mediaG.ParseMode = TgParseModMarkdownV2
+	return inviteLinks, nil
+}
 
-			} else {
+func (t *TelegramClient) DownloadStrBase64(filePath string) (string, error) {
+	fileBytes, err := t.DownloadByte(filePath)
+	if err != nil {
 
-				err := writer.WriteField(TgFieldNameParseMod, parseMode)
-				if err != nil {
-					return nil, err
-				}
-				mediaG.ParseMode = parseMode
-			}
-		}
+		return "", err
+	}
 
-		mediaGroups = append(mediaGroups, mediaG)
+	return base64.StdEncoding.EncodeToString(fileBytes), nil
+}
 
-	}
-	mediaGroupBytes, err := json.Marshal(mediaGroups)
+func (t *TelegramClient) DownloadByte(filePath string) ([]byte, error) {
+	resp, err := http.Get(fmt.Sprintf(TgBotDownloadFileUrl, t.BotToken, filePath))
 	if err != nil {
-		fmt.Println("Error marshalling mediaGroups:", err)
-		return response, err
-	}
 
-	err = writer.WriteField("media", string(mediaGroupBytes))
-	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	err = writer.WriteField(TgFieldCaption, message)
+	fileBytes, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
-	}
 
-	err = writer.Close()
-	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf(TgBotSendMediaGroupUrlSptf, t.BotToken, chatId), nil)
-	if err != nil {
-		fmt.Println("Error creating HTTP request:", err)
-		return response, err
-	}
-
-	req.Header.Set(HeaderContentType, writer.FormDataContentType())
-	req.Body = ioutil.NopCloser(body)
-
-	client := &http.Client{}
+	return fileBytes, nil
+}
 
-	resp, err := client.Do(req)
+func (t *TelegramClient) DownloadFile(fileName, filePath string) error {
+	resp, err := http.Get(fmt.Sprintf(TgBotDownloadFileUrl, t.BotToken, fileName))
 	if err != nil {
-		fmt.Println("Error sending HTTP request:", err)
 
-		return response, err
+		return err
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	out, err := os.Create(filePath)
 	if err != nil {
-		log.Printf("Error reading response body: %v", err)
 
-		return response, err
+		return err
 	}
+	defer out.Close()
 
-	err = json.Unmarshal(bodyBytes, &response)
+	_, err = io.Copy(out, resp.Body)
 	if err != nil {
-		log.Printf("Error unmarshal response body: %v", err)
 
-		return response, err
+		return err
 	}
 
-	return response, nil
+	return nil
 }
-func (t *TelegramClient) SendSticker(chatId string, media string) (StikerResponse, error) {
-	url := TgBotBaseUrl + t.BotToken + TgBotSendStickerUrl
-	filePath := TempStickerFileName
 
-	imageBytes, err := base64.StdEncoding.DecodeString(media)
+func (t *TelegramClient) GetFilePath(fileID string) (string, error) {
+	var fileResponse FileResponse
+	resp, err := http.Get(fmt.Sprintf(TgBotGetFileUrl, t.BotToken, fileID))
 	if err != nil {
-		fmt.Printf("err: %v", err)
-		return StikerResponse{}, err
-	}
 
-	var buffer bytes.Buffer
-	_, err = buffer.Write(imageBytes)
-	if err != nil {
-		return StikerResponse{}, err
+		return "", err
 	}
+	defer resp.Body.Close()
 
-	payload := &bytes.Buffer{}
-	writer := multipart.NewWriter(payload)
-
-	part, err := writer.CreateFormFile(TgFieldSticker, filepath.Base(filePath))
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("create form file err: %v", err)
-		return StikerResponse{}, err
-	}
 
-	_, err = io.Copy(part, &buffer)
-	if err != nil {
-		log.Printf("io copy err: %v", err)
-		return StikerResponse{}, err
+		return "", err
 	}
 
-	_ = writer.WriteField(TgFieldChatId, chatId)
-	writer.Close()
-
-	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, url, payload)
+	err = json.Unmarshal(body, &fileResponse)
 	if err != nil {
-		log.Printf("Error creating request: %v", err)
-		return StikerResponse{}, err
+		return "", err
 	}
 
-	req.Header.Set(HeaderContentType, writer.FormDataContentType())
+	return fileResponse.Result.FilePath, nil
+}
 
-	resp, err := t.HttpClient.Do(req)
-	if err != nil {
-		log.Printf("Error sending request: %v", err)
-		return StikerResponse{}, err
-	}
+// APIError is returned when the Telegram API answers with a non-200 status code.
+// Description and RetryAfter are filled from the error body when Telegram provides them.
+type APIError struct {
+	StatusCode  int
+	ErrorCode   int
+	Description string
+	RetryAfter  int
+	MigrateTo   int64
+}
 
-	defer resp.Body.Close()
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Error reading response body: %v", err)
-		return StikerResponse{}, err
+func (e *APIError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("API request failed with status code: %d", e.StatusCode)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("API request failed with status code: %d body %v", resp.StatusCode, string(bodyBytes))
-		return StikerResponse{}, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
-	}
+	return fmt.Sprintf("API request failed with status code: %d: %s", e.StatusCode, e.Description)
+}
 
-	var stikerResponse StikerResponse
-	if err := json.Unmarshal(bodyBytes, &stikerResponse); err != nil {
-		log.Printf("Error unmarshalling response body: %v", err)
-		return StikerResponse{}, err
+// Temporary reports whether the request may succeed if retried later: on flood control
+// (wait RetryAfter seconds first) and on server errors. Other errors need a changed request.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
+// ChatUnavailable reports whether the bot can't send to the chat at all: it was kicked or blocked,
+// or the chat doesn't exist.
+func (e *APIError) ChatUnavailable() bool {
+	return e.StatusCode == http.StatusForbidden || strings.Contains(e.Description, "chat not found")
+}
+
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode}
 
+	var errorResponse ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil {
+		apiErr.ErrorCode = errorResponse.ErrorCode
+		apiErr.Description = errorResponse.Description
+		if errorResponse.Parameters != nil {
+			apiErr.RetryAfter = errorResponse.Parameters.RetryAfter
+			apiErr.MigrateTo = errorResponse.Parameters.MigrateToChatId
+		}
 	}
 
-	return stikerResponse, nil
+	return apiErr
 }
 
-func (t *TelegramClient) GenerateInviteLinks(invite CreateChatInviteLinkRequest) (*InviteLinks, error) {
-	requestBody, err := json.Marshal(invite)
+// postJSON sends payload as JSON to the given Bot API method and decodes the response body into out.
+// out may be nil when the caller only cares about success.
+func (t *TelegramClient) postJSON(method string, payload interface{}, out interface{}) error {
+	url := TgBotBaseUrl + t.BotToken + method
+
+	requestData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, err
-	}
+		log.Printf("Error marshalling request: %v", err)
 
-	apiURL := TgBotBaseUrl + t.BotToken + TgBotCreateInviteLinkUrl
+		return err
+	}
 
-	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, apiURL, bytes.NewBuffer(requestBody))
+	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, url, bytes.NewBuffer(requestData))
 	if err != nil {
-		return nil, err
+		log.Printf("Error creating request: %v", err)
+
+		return err
 	}
 
 	req.Header.Set(HeaderContentType, ApplicationJson)
+
+	return t.do(req, out)
+}
+
+// do sends req with the client's HTTP client and decodes a successful response body into out.
+func (t *TelegramClient) do(req *http.Request, out interface{}) error {
 	resp, err := t.HttpClient.Do(req)
 	if err != nil {
+		log.Printf("Error sending request: %v", err)
 
-		return nil, err
+		return err
 	}
 
 	defer resp.Body.Close()
-
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
+		log.Printf("Error reading response body: %v", err)
 
-		return nil, err
+		return err
 	}
 
 	if resp.StatusCode != http.StatusOK {
+		log.Printf("API request failed with status code: %d body %v", resp.StatusCode, string(bodyBytes))
 
-		return nil, fmt.Errorf("API request failed with status code: %d", resp.StatusCode)
-	}
-
-	inviteLinks := new(InviteLinks)
-	if err := json.Unmarshal(bodyBytes, &inviteLinks); err != nil {
-
-		return nil, err
+		return newAPIError(resp.StatusCode, bodyBytes)
 	}
 
-	return inviteLinks, nil
-}
-
-func (t *TelegramClient) DeleteMessage(messageId int, chatId int64) error {
-
-	url := TgBotBaseUrl + t.BotToken + TgBotDeleteMsgUrl
-
-	requestBody, err := json.Marshal(map[string]string{
-		TgFieldChatId:    fmt.Sprintf("%d", chatId),
-		TgFieldMessageId: fmt.Sprintf("%d", messageId),
-	})
-	if err != nil {
-		return err
+	if out == nil {
+		return nil
 	}
 
-	resp, err := http.Post(url, ApplicationJson, bytes.NewBuffer(requestBody))
-	if err != nil {
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		log.Printf("Error unmarshalling response body: %v", err)
 
 		return err
 	}
 
-	defer resp.Body.Close()
-
 	return nil
 }
 
-func (t *TelegramClient) ForwardMessage(chatId string, fromChatId string, messageId string) ([]byte, error) {
-	url := TgBotBaseUrl + t.BotToken + TgBotForwardMsgUrl
+type multipartFile struct {
+	Field string
+	Name  string
+	Data  []byte
+}
 
-	requestBody, err := json.Marshal(map[string]string{
-		TgFieldChatId:     chatId,
-		TgFieldMessageId:  messageId,
-		TgFieldFromChatId: fromChatId,
-	})
-	if err != nil {
+// postMultipart sends fields and files as multipart/form-data to the given Bot API method
+// and decodes the response body into out.
+func (t *TelegramClient) postMultipart(method string, fields map[string]string, files []multipartFile, out interface{}) error {
+	url := TgBotBaseUrl + t.BotToken + method
 
-		return nil, err
-	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
 
-	resp, err := http.Post(url, ApplicationJson, bytes.NewBuffer(requestBody))
-	if err != nil {
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			log.Printf("Error writing form field: %v", err)
 
-		return nil, err
+			return err
+		}
 	}
-	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.Field, file.Name)
+		if err != nil {
+			log.Printf("Error creating form file: %v", err)
 
-		return nil, err
-	}
+			return err
+		}
 
-	return bodyBytes, nil
-}
+		if _, err := part.Write(file.Data); err != nil {
+			log.Printf("Error copying file data: %v", err)
 
-func (t *TelegramClient) DownloadStrBase64(filePath string) (string, error) {
-	fileBytes, err := t.DownloadByte(filePath)
-	if err != nil {
-
-		return "", err
+			return err
+		}
 	}
 
-	return base64.StdEncoding.EncodeToString(fileBytes), nil
-}
+	if err := writer.Close(); err != nil {
+		return err
+	}
 
-func (t *TelegramClient) DownloadByte(filePath string) ([]byte, error) {
-	resp, err := http.Get(fmt.Sprintf(TgBotDownloadFileUrl, t.BotToken, filePath))
+	req, err := http.NewRequestWithContext(t.Ctx, http.MethodPost, url, body)
 	if err != nil {
+		log.Printf("Error creating request: %v", err)
 
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
 
-	fileBytes, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	req.Header.Set(HeaderContentType, writer.FormDataContentType())
 
-		return nil, err
-	}
+	return t.do(req, out)
+}
 
-	return fileBytes, nil
+// sendOptions are the optional send parameters shared by the multipart send methods.
+type sendOptions struct {
+	MessageThreadId     int
+	DisableNotification bool
+	ProtectContent      bool
+	MessageEffectId     string
+	ReplyParameters     *ReplyParameters
+	ReplyMarkup         interface{}
 }
 
-func (t *TelegramClient) DownloadFile(fileName, filePath string) error {
-	resp, err := http.Get(fmt.Sprintf(TgBotDownloadFileUrl, t.BotToken, fileName))
-	if err != nil {
+// writeFields adds the options that are set to multipart fields.
+func (o sendOptions) writeFields(fields map[string]string) error {
+	if o.MessageThreadId != 0 {
+		fields[TgFieldMessageThreadId] = strconv.Itoa(o.MessageThreadId)
+	}
 
-		return err
+	if o.DisableNotification {
+		fields[TgFieldDisableNotification] = "true"
 	}
-	defer resp.Body.Close()
 
-	out, err := os.Create(filePath)
-	if err != nil {
+	if o.ProtectContent {
+		fields[TgFieldProtectContent] = "true"
+	}
 
-		return err
+	if len(o.MessageEffectId) > 0 {
+		fields[TgFieldMessageEffectId] = o.MessageEffectId
 	}
-	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
-	if err != nil {
+	if o.ReplyParameters != nil {
+		if err := writeJSONField(fields, TgFieldReplyParameters, o.ReplyParameters); err != nil {
+			return err
+		}
+	}
 
-		return err
+	if o.ReplyMarkup != nil {
+		if err := writeJSONField(fields, TgFieldReplyMarkup, o.ReplyMarkup); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (t *TelegramClient) GetFilePath(fileID string) (string, error) {
-	var fileResponse FileResponse
-	resp, err := http.Get(fmt.Sprintf(TgBotGetFileUrl, t.BotToken, fileID))
-	if err != nil {
-
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
+func writeJSONField(fields map[string]string, name string, value interface{}) error {
+	data, err := json.Marshal(value)
 	if err != nil {
+		log.Printf("Error marshalling %s: %v", name, err)
 
-		return "", err
+		return err
 	}
 
-	err = json.Unmarshal(body, &fileResponse)
-	if err != nil {
-		return "", err
-	}
+	fields[name] = string(data)
 
-	return fileResponse.Result.FilePath, nil
+	return nil
 }
@@ -0,0 +1,66 @@
+package teledau
+
+// BanChatMember bans a user from a group or channel; the bot must be an administrator with can_restrict_members.
+func (t *TelegramClient) BanChatMember(ban BanChatMemberRequest) error {
+	return t.postJSON(TgBotBanChatMemberUrl, ban, nil)
+}
+
+// UnbanChatMember lifts a ban. Unless onlyIfBanned is set, a current member is removed from the chat as well.
+func (t *TelegramClient) UnbanChatMember(chatId string, userId int64, onlyIfBanned bool) error {
+	return t.postJSON(TgBotUnbanChatMemberUrl, chatMemberRequest{ChatId: chatId, UserId: userId, OnlyIfBanned: onlyIfBanned}, nil)
+}
+
+func (t *TelegramClient) RestrictChatMember(restrict RestrictChatMemberRequest) error {
+	return t.postJSON(TgBotRestrictChatMemberUrl, restrict, nil)
+}
+
+func (t *TelegramClient) PromoteChatMember(promote PromoteChatMemberRequest) error {
+	return t.postJSON(TgBotPromoteChatMemberUrl, promote, nil)
+}
+
+// SetChatPermissions sets the default permissions of all members.
+func (t *TelegramClient) SetChatPermissions(permissions SetChatPermissionsRequest) error {
+	return t.postJSON(TgBotSetChatPermissionsUrl, permissions, nil)
+}
+
+// SetChatAdministratorCustomTitle sets the title of an administrator promoted by the bot.
+func (t *TelegramClient) SetChatAdministratorCustomTitle(chatId string, userId int64, customTitle string) error {
+	return t.postJSON(TgBotSetChatAdministratorCustomTitleUrl, chatMemberRequest{ChatId: chatId, UserId: userId, CustomTitle: customTitle}, nil)
+}
+
+// BanChatSenderChat bans a channel chat from posting on behalf of itself in a group or channel.
+func (t *TelegramClient) BanChatSenderChat(chatId string, senderChatId int64) error {
+	return t.postJSON(TgBotBanChatSenderChatUrl, chatMemberRequest{ChatId: chatId, SenderChatId: senderChatId}, nil)
+}
+
+func (t *TelegramClient) UnbanChatSenderChat(chatId string, senderChatId int64) error {
+	return t.postJSON(TgBotUnbanChatSenderChatUrl, chatMemberRequest{ChatId: chatId, SenderChatId: senderChatId}, nil)
+}
+
+// GetChatAdministrators returns the administrators of a chat, bots excluded.
+func (t *TelegramClient) GetChatAdministrators(chatId string) ([]NewChatMember, error) {
+	var response ChatMembersResponse
+	if err := t.postJSON(TgBotGetChatAdministratorsUrl, chatMemberRequest{ChatId: chatId}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+func (t *TelegramClient) GetChatMember(chatId string, userId int64) (*NewChatMember, error) {
+	var response ChatMemberResponse
+	if err := t.postJSON(TgBotGetChatMemberUrl, chatMemberRequest{ChatId: chatId, UserId: userId}, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
+
+func (t *TelegramClient) GetChatMemberCount(chatId string) (int, error) {
+	var response IntResponse
+	if err := t.postJSON(TgBotGetChatMemberCountUrl, chatMemberRequest{ChatId: chatId}, &response); err != nil {
+		return 0, err
+	}
+
+	return response.Result, nil
+}
@@ -0,0 +1,79 @@
+package teledau
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTelegramClient_RestrictAndPromote(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	err := client.RestrictChatMember(RestrictChatMemberRequest{
+		ChatId:      "-100",
+		UserId:      42,
+		Permissions: ChatPermissions{CanSendMessages: true},
+		UntilDate:   1700000000,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.PromoteChatMember(PromoteChatMemberRequest{
+		ChatId:                  "-100",
+		UserId:                  42,
+		ChatAdministratorRights: ChatAdministratorRights{CanDeleteMessages: true, CanRestrictMembers: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"chat_id":"-100","user_id":42,"permissions":{"can_send_messages":true},"until_date":1700000000}`
+	if body := string(transport.calls("restrictChatMember")[0].Body); body != want {
+		t.Errorf("restrictChatMember request = %s", body)
+	}
+
+	want = `{"chat_id":"-100","user_id":42,"can_delete_messages":true,"can_restrict_members":true}`
+	if body := string(transport.calls("promoteChatMember")[0].Body); body != want {
+		t.Errorf("promoteChatMember request = %s", body)
+	}
+}
+
+func TestTelegramClient_GetChatMember(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"getChatMember":         `{"ok":true,"result":{"user":{"id":42,"first_name":"Bukayo"},"status":"restricted","is_member":true,"can_send_messages":false,"until_date":1700000000}}`,
+		"getChatAdministrators": `{"ok":true,"result":[{"user":{"id":1},"status":"creator","is_anonymous":false},{"user":{"id":2},"status":"administrator","can_delete_messages":true,"custom_title":"mod"}]}`,
+		"getChatMemberCount":    `{"ok":true,"result":1204}`,
+	})
+
+	member, err := client.GetChatMember("-100", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !member.IsInChat() || member.IsAdministrator() || member.CanSendMessages || member.UntilDate != 1700000000 {
+		t.Errorf("unexpected member: %+v", member)
+	}
+
+	admins, err := client.GetChatAdministrators("-100")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(admins) != 2 || !admins[0].IsAdministrator() || admins[1].CustomTitle != "mod" || !admins[1].CanDeleteMessages {
+		t.Errorf("unexpected administrators: %+v", admins)
+	}
+
+	count, err := client.GetChatMemberCount("-100")
+	if err != nil || count != 1204 {
+		t.Errorf("GetChatMemberCount() = %d, %v", count, err)
+	}
+
+	var sent map[string]interface{}
+	if err := json.Unmarshal(transport.calls("getChatMember")[0].Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent["chat_id"] != "-100" || sent["user_id"] != float64(42) {
+		t.Errorf("unexpected getChatMember request: %v", sent)
+	}
+}
@@ -0,0 +1,46 @@
+package teledau
+
+import "encoding/base64"
+
+func (t *TelegramClient) SetChatTitle(chatId, title string) error {
+	return t.postJSON(TgBotSetChatTitleUrl, chatTitleRequest{ChatId: chatId, Title: title}, nil)
+}
+
+// SetChatDescription changes the description; an empty description removes it.
+func (t *TelegramClient) SetChatDescription(chatId, description string) error {
+	return t.postJSON(TgBotSetChatDescriptionUrl, chatDescriptionRequest{ChatId: chatId, Description: description}, nil)
+}
+
+// SetChatPhoto uploads a base64-encoded photo as the chat photo.
+func (t *TelegramClient) SetChatPhoto(chatId, photo string) error {
+	imgData, err := base64.StdEncoding.DecodeString(photo)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]string{TgFieldChatId: chatId}
+	files := []multipartFile{{Field: TgFieldMediaType, Name: TgChatPhotoFileName, Data: imgData}}
+
+	return t.postMultipart(TgBotSetChatPhotoUrl, fields, files, nil)
+}
+
+func (t *TelegramClient) DeleteChatPhoto(chatId string) error {
+	return t.postJSON(TgBotDeleteChatPhotoUrl, chatRequest{ChatId: chatId}, nil)
+}
+
+func (t *TelegramClient) PinChatMessage(chatId string, messageId int, disableNotification bool) error {
+	return t.postJSON(TgBotPinChatMessageUrl, chatRequest{ChatId: chatId, MessageId: messageId, DisableNotification: disableNotification}, nil)
+}
+
+// UnpinChatMessage unpins messageId, or the most recently pinned message when messageId is 0.
+func (t *TelegramClient) UnpinChatMessage(chatId string, messageId int) error {
+	return t.postJSON(TgBotUnpinChatMessageUrl, chatRequest{ChatId: chatId, MessageId: messageId}, nil)
+}
+
+func (t *TelegramClient) UnpinAllChatMessages(chatId string) error {
+	return t.postJSON(TgBotUnpinAllChatMessagesUrl, chatRequest{ChatId: chatId}, nil)
+}
+
+func (t *TelegramClient) LeaveChat(chatId string) error {
+	return t.postJSON(TgBotLeaveChatUrl, chatRequest{ChatId: chatId}, nil)
+}
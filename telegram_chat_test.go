@@ -0,0 +1,71 @@
+package teledau
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestTelegramClient_GetChatFullInfo(t *testing.T) {
+	client, _ := newFakeTelegramClient(map[string]string{
+		"getChat": `{"ok":true,"result":{"id":-100,"title":"Club","type":"supergroup","slow_mode_delay":30,"linked_chat_id":-200,
+			"permissions":{"can_send_messages":true},"pinned_message":{"message_id":7,"text":"rules"},"has_protected_content":true}}`,
+	})
+
+	chat, err := client.GetChat("@club")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := chat.GetChat
+	if info.SlowModeDelay != 30 || info.LinkedChatId != -200 || !info.HasProtectedContent {
+		t.Errorf("unexpected chat: %+v", info)
+	}
+
+	if info.Permissions == nil || !info.Permissions.CanSendMessages || info.PinnedMessage == nil || info.PinnedMessage.Text != "rules" {
+		t.Errorf("unexpected permissions or pinned message: %+v, %+v", info.Permissions, info.PinnedMessage)
+	}
+}
+
+func TestTelegramClient_GetChatAPIError(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	transport.handler = func(method string, body []byte) (int, string) {
+		return 400, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`
+	}
+
+	_, err := client.GetChat("@missing")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Description != "Bad Request: chat not found" {
+		t.Errorf("GetChat() error = %v, want *APIError", err)
+	}
+}
+
+func TestTelegramClient_ChatSettings(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	if err := client.SetChatDescription("-100", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.PinChatMessage("-100", 7, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.SetChatPhoto("-100", base64.StdEncoding.EncodeToString([]byte("jpeg"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := string(transport.calls("setChatDescription")[0].Body); body != `{"chat_id":"-100","description":""}` {
+		t.Errorf("setChatDescription request = %s", body)
+	}
+
+	if body := string(transport.calls("pinChatMessage")[0].Body); body != `{"chat_id":"-100","message_id":7,"disable_notification":true}` {
+		t.Errorf("pinChatMessage request = %s", body)
+	}
+
+	form := readMultipartForm(t, transport.calls("setChatPhoto")[0])
+	if form[TgFieldChatId] != "-100" || form[TgFieldMediaType] != "jpeg" {
+		t.Errorf("unexpected setChatPhoto form: %v", form)
+	}
+}
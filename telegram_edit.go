@@ -0,0 +1,166 @@
+package teledau
+
+import (
+	"encoding/base64"
+	"log"
+	"strconv"
+)
+
+// EditMessageMedia replaces the photo or video of a message or of one item of an album.
+func (t *TelegramClient) EditMessageMedia(edit EditMessageMediaRequest) (SendMessageResponse, error) {
+	var response SendMessageResponse
+
+	fields, files, err := editMessageMediaForm(edit)
+	if err != nil {
+		return response, err
+	}
+
+	if err := t.postMultipart(TgBotEditMessageMediaUrl, fields, files, &response); err != nil {
+		return SendMessageResponse{}, err
+	}
+
+	return response, nil
+}
+
+// EditInlineMessageMedia is EditMessageMedia for a message sent via inline mode.
+func (t *TelegramClient) EditInlineMessageMedia(edit EditMessageMediaRequest) error {
+	fields, files, err := editMessageMediaForm(edit)
+	if err != nil {
+		return err
+	}
+
+	return t.postMultipart(TgBotEditMessageMediaUrl, fields, files, nil)
+}
+
+// EditInlineMessage is EditMessage for a message sent via inline mode.
+func (t *TelegramClient) EditInlineMessage(message EditMessageRequest) error {
+	return t.postJSON(TgBotEditMessageUrl, message, nil)
+}
+
+// EditInlineCaption is EditCaption for a message sent via inline mode.
+func (t *TelegramClient) EditInlineCaption(message EditCaptionRequest) error {
+	return t.postJSON(TgBotEditCaptionUrl, message, nil)
+}
+
+// EditMessageReplyMarkup replaces or removes the inline keyboard of a message.
+func (t *TelegramClient) EditMessageReplyMarkup(edit EditMessageReplyMarkupRequest) (SendMessageResponse, error) {
+	var response SendMessageResponse
+	err := t.postJSON(TgBotEditMessageReplyMarkupUrl, edit, &response)
+
+	return response, err
+}
+
+// EditInlineMessageReplyMarkup is EditMessageReplyMarkup for a message sent via inline mode.
+func (t *TelegramClient) EditInlineMessageReplyMarkup(edit EditMessageReplyMarkupRequest) error {
+	return t.postJSON(TgBotEditMessageReplyMarkupUrl, edit, nil)
+}
+
+// EditMessageLiveLocation moves a live location until its live period ends or StopMessageLiveLocation is called.
+func (t *TelegramClient) EditMessageLiveLocation(edit EditMessageLiveLocationRequest) (SendMessageResponse, error) {
+	var response SendMessageResponse
+	err := t.postJSON(TgBotEditMessageLiveLocationUrl, edit, &response)
+
+	return response, err
+}
+
+// EditInlineMessageLiveLocation is EditMessageLiveLocation for a message sent via inline mode.
+func (t *TelegramClient) EditInlineMessageLiveLocation(edit EditMessageLiveLocationRequest) error {
+	return t.postJSON(TgBotEditMessageLiveLocationUrl, edit, nil)
+}
+
+// StopMessageLiveLocation stops updating a live location. replyMarkup replaces the inline keyboard
+// and may be nil.
+func (t *TelegramClient) StopMessageLiveLocation(chatId string, messageId int, replyMarkup interface{}) (SendMessageResponse, error) {
+	var response SendMessageResponse
+	err := t.postJSON(TgBotStopMessageLiveLocationUrl, EditMessageReplyMarkupRequest{
+		ChatId:      chatId,
+		MessageId:   messageId,
+		ReplyMarkup: replyMarkup,
+	}, &response)
+
+	return response, err
+}
+
+// StopInlineMessageLiveLocation is StopMessageLiveLocation for a message sent via inline mode.
+func (t *TelegramClient) StopInlineMessageLiveLocation(inlineMessageId string, replyMarkup interface{}) error {
+	return t.postJSON(TgBotStopMessageLiveLocationUrl, EditMessageReplyMarkupRequest{
+		InlineMessageId: inlineMessageId,
+		ReplyMarkup:     replyMarkup,
+	}, nil)
+}
+
+// StopPoll closes a poll sent by the bot and returns its final results.
+func (t *TelegramClient) StopPoll(chatId string, messageId int, replyMarkup interface{}) (*Poll, error) {
+	var response PollResultResponse
+	err := t.postJSON(TgBotStopPollUrl, EditMessageReplyMarkupRequest{
+		ChatId:      chatId,
+		MessageId:   messageId,
+		ReplyMarkup: replyMarkup,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
+
+// editMessageMediaForm builds the multipart form of an editMessageMedia request. The media is
+// attached as a file unless it is referenced by file_id or URL.
+func editMessageMediaForm(edit EditMessageMediaRequest) (map[string]string, []multipartFile, error) {
+	file := edit.Media
+	if file.FileId == "" && file.Data == nil && edit.Photo != "" {
+		imgData, err := base64.StdEncoding.DecodeString(edit.Photo)
+		if err != nil {
+			log.Printf("Error decoding base64 string: %v", err)
+
+			return nil, nil, err
+		}
+
+		file = InputFile{Name: TgPhotoFileName, Data: imgData}
+	}
+
+	mediaType := edit.Type
+	if mediaType == "" {
+		mediaType = MediaTypePhoto
+	}
+
+	media := inputMedia{
+		MediaGroup: MediaGroup{
+			Type:            mediaType,
+			Caption:         edit.Caption,
+			CaptionEntities: edit.CaptionEntities,
+		},
+		ShowCaptionAboveMedia: edit.ShowCaptionAboveMedia,
+		HasSpoiler:            edit.HasSpoiler,
+		Width:                 edit.Width,
+		Height:                edit.Height,
+		Duration:              edit.Duration,
+		SupportsStreaming:     edit.SupportsStreaming,
+	}
+	if len(edit.CaptionEntities) == 0 {
+		media.ParseMode = edit.ParseMode
+	}
+
+	var files []multipartFile
+	media.Media, files = file.attach(mediaType, TgPhotoFileName)
+
+	fields := make(map[string]string)
+	if edit.InlineMessageId != "" {
+		fields[TgFieldInlineMessageId] = edit.InlineMessageId
+	} else {
+		fields[TgFieldChatId] = edit.ChatId
+		fields[TgFieldMessageId] = strconv.Itoa(edit.MessageId)
+	}
+
+	if err := writeJSONField(fields, TgFieldMedia, media); err != nil {
+		return nil, nil, err
+	}
+
+	if edit.ReplyMarkup != nil {
+		if err := writeJSONField(fields, TgFieldReplyMarkup, edit.ReplyMarkup); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return fields, files, nil
+}
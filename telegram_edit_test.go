@@ -0,0 +1,106 @@
+package teledau
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTelegramClient_EditMessageMedia(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"editMessageMedia": `{"ok":true,"result":{"message_id":7}}`,
+	})
+
+	_, err := client.EditMessageMedia(EditMessageMediaRequest{
+		ChatId:    "@club",
+		MessageId: 7,
+		Photo:     base64.StdEncoding.EncodeToString([]byte("jpeg")),
+		Caption:   "Goal",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := readMultipartForm(t, transport.calls("editMessageMedia")[0])
+
+	var media inputMedia
+	if err := json.Unmarshal([]byte(form["media"]), &media); err != nil {
+		t.Fatal(err)
+	}
+
+	if media.Type != MediaTypePhoto || media.Media != "attach://photo" || media.Caption != "Goal" || form["photo"] != "jpeg" {
+		t.Errorf("unexpected form: %v", form)
+	}
+
+	_, err = client.EditMessageMedia(EditMessageMediaRequest{
+		ChatId:            "@club",
+		MessageId:         7,
+		Type:              MediaTypeVideo,
+		Media:             InputFile{Name: "goal.mp4", Data: []byte("mp4")},
+		Width:             1280,
+		SupportsStreaming: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form = readMultipartForm(t, transport.calls("editMessageMedia")[1])
+	if form["video"] != "mp4" || !strings.Contains(form["media"], `"media":"attach://video"`) || !strings.Contains(form["media"], `"supports_streaming":true`) {
+		t.Errorf("unexpected video form: %v", form)
+	}
+}
+
+func TestTelegramClient_EditInlineMessageMedia(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	err := client.EditInlineMessageMedia(EditMessageMediaRequest{InlineMessageId: "AAQ", Media: InputFile{FileId: "https://example.com/goal.jpg"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := readMultipartForm(t, transport.calls("editMessageMedia")[0])
+	if form["inline_message_id"] != "AAQ" || form["chat_id"] != "" || !strings.Contains(form["media"], "https://example.com/goal.jpg") {
+		t.Errorf("unexpected form: %v", form)
+	}
+
+	if _, ok := form["photo"]; ok {
+		t.Error("media referenced by URL should not be uploaded")
+	}
+}
+
+func TestTelegramClient_StopPoll(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"stopPoll": `{"ok":true,"result":{"id":"5","question":"Man of the match?","is_closed":true,"total_voter_count":3}}`,
+	})
+
+	poll, err := client.StopPoll("@club", 7, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if poll.Id != "5" || poll.TotalVoterCount != 3 {
+		t.Errorf("unexpected poll: %+v", poll)
+	}
+
+	var request map[string]interface{}
+	if err := json.Unmarshal(transport.calls("stopPoll")[0].Body, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := request["reply_markup"]; ok || request["message_id"] != float64(7) {
+		t.Errorf("unexpected request: %v", request)
+	}
+}
+
+func TestTelegramClient_EditInlineMessageReplyMarkup(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	if err := client.EditInlineMessageReplyMarkup(EditMessageReplyMarkupRequest{InlineMessageId: "AAQ"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := string(transport.calls("editMessageReplyMarkup")[0].Body); body != `{"inline_message_id":"AAQ"}` {
+		t.Errorf("unexpected request: %s", body)
+	}
+}
@@ -0,0 +1,67 @@
+package teledau
+
+// CreateForumTopic creates a topic in a forum supergroup. The bot needs the can_manage_topics right.
+func (t *TelegramClient) CreateForumTopic(topic CreateForumTopicRequest) (*ForumTopic, error) {
+	var response ForumTopicResponse
+	if err := t.postJSON(TgBotCreateForumTopicUrl, topic, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
+
+func (t *TelegramClient) EditForumTopic(topic EditForumTopicRequest) error {
+	return t.postJSON(TgBotEditForumTopicUrl, topic, nil)
+}
+
+func (t *TelegramClient) CloseForumTopic(chatId string, messageThreadId int) error {
+	return t.postJSON(TgBotCloseForumTopicUrl, forumTopicRequest{ChatId: chatId, MessageThreadId: messageThreadId}, nil)
+}
+
+func (t *TelegramClient) ReopenForumTopic(chatId string, messageThreadId int) error {
+	return t.postJSON(TgBotReopenForumTopicUrl, forumTopicRequest{ChatId: chatId, MessageThreadId: messageThreadId}, nil)
+}
+
+// DeleteForumTopic deletes a topic together with all its messages.
+func (t *TelegramClient) DeleteForumTopic(chatId string, messageThreadId int) error {
+	return t.postJSON(TgBotDeleteForumTopicUrl, forumTopicRequest{ChatId: chatId, MessageThreadId: messageThreadId}, nil)
+}
+
+func (t *TelegramClient) UnpinAllForumTopicMessages(chatId string, messageThreadId int) error {
+	return t.postJSON(TgBotUnpinAllForumTopicMessagesUrl, forumTopicRequest{ChatId: chatId, MessageThreadId: messageThreadId}, nil)
+}
+
+func (t *TelegramClient) EditGeneralForumTopic(chatId, name string) error {
+	return t.postJSON(TgBotEditGeneralForumTopicUrl, forumTopicRequest{ChatId: chatId, Name: name}, nil)
+}
+
+func (t *TelegramClient) CloseGeneralForumTopic(chatId string) error {
+	return t.postJSON(TgBotCloseGeneralForumTopicUrl, forumTopicRequest{ChatId: chatId}, nil)
+}
+
+func (t *TelegramClient) ReopenGeneralForumTopic(chatId string) error {
+	return t.postJSON(TgBotReopenGeneralForumTopicUrl, forumTopicRequest{ChatId: chatId}, nil)
+}
+
+// HideGeneralForumTopic hides the general topic; it is closed automatically if it was open.
+func (t *TelegramClient) HideGeneralForumTopic(chatId string) error {
+	return t.postJSON(TgBotHideGeneralForumTopicUrl, forumTopicRequest{ChatId: chatId}, nil)
+}
+
+func (t *TelegramClient) UnhideGeneralForumTopic(chatId string) error {
+	return t.postJSON(TgBotUnhideGeneralForumTopicUrl, forumTopicRequest{ChatId: chatId}, nil)
+}
+
+func (t *TelegramClient) UnpinAllGeneralForumTopicMessages(chatId string) error {
+	return t.postJSON(TgBotUnpinAllGeneralForumTopicMessagesUrl, forumTopicRequest{ChatId: chatId}, nil)
+}
+
+// GetForumTopicIconStickers returns the custom emoji stickers that can be used as topic icons.
+func (t *TelegramClient) GetForumTopicIconStickers() ([]Sticker, error) {
+	var response StickersResponse
+	if err := t.postJSON(TgBotGetForumTopicIconStickersUrl, struct{}{}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
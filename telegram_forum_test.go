@@ -0,0 +1,67 @@
+package teledau
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTelegramClient_ForumTopics(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"createForumTopic": `{"ok":true,"result":{"message_thread_id":12,"name":"Match day","icon_color":7322096}}`,
+	})
+
+	topic, err := client.CreateForumTopic(CreateForumTopicRequest{ChatId: "-100", Name: "Match day", IconColor: ForumTopicIconColorBlue})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if topic.MessageThreadId != 12 || topic.IconColor != ForumTopicIconColorBlue {
+		t.Errorf("unexpected topic: %+v", topic)
+	}
+
+	noIcon := ""
+	if err := client.EditForumTopic(EditForumTopicRequest{ChatId: "-100", MessageThreadId: 12, IconCustomEmojiId: &noIcon}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.CloseForumTopic("-100", 12); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.HideGeneralForumTopic("-100"); err != nil {
+		t.Fatal(err)
+	}
+
+	var edit map[string]interface{}
+	if err := json.Unmarshal(transport.calls("editForumTopic")[0].Body, &edit); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := edit["name"]; ok || edit["icon_custom_emoji_id"] != "" {
+		t.Errorf("unexpected editForumTopic request: %v", edit)
+	}
+
+	if body := string(transport.calls("closeForumTopic")[0].Body); body != `{"chat_id":"-100","message_thread_id":12}` {
+		t.Errorf("closeForumTopic request = %s", body)
+	}
+
+	if body := string(transport.calls("hideGeneralForumTopic")[0].Body); body != `{"chat_id":"-100"}` {
+		t.Errorf("hideGeneralForumTopic request = %s", body)
+	}
+}
+
+func TestMessage_TopicId(t *testing.T) {
+	var message Message
+	if err := json.Unmarshal([]byte(`{"message_id":1,"message_thread_id":12,"is_topic_message":true}`), &message); err != nil {
+		t.Fatal(err)
+	}
+
+	if message.TopicId() != 12 {
+		t.Errorf("TopicId() = %d, want 12", message.TopicId())
+	}
+
+	// Replies outside forums also carry message_thread_id.
+	if (Message{MessageThreadId: 5}).TopicId() != 0 {
+		t.Error("TopicId() of a non-topic message should be 0")
+	}
+}
@@ -0,0 +1,11 @@
+package teledau
+
+// AnswerInlineQuery sends the results for an inline query received in Update.InlineQuery.
+// At most 50 results are allowed per query; use NextOffset to paginate.
+func (t *TelegramClient) AnswerInlineQuery(answer AnswerInlineQueryRequest) error {
+	if answer.Results == nil {
+		answer.Results = []InlineQueryResult{}
+	}
+
+	return t.postJSON(TgBotAnswerInlineQueryUrl, answer, nil)
+}
@@ -0,0 +1,71 @@
+package teledau
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestTelegramClient_AnswerInlineQuery(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	err := client.AnswerInlineQuery(AnswerInlineQueryRequest{
+		InlineQueryId: "42",
+		Results: []InlineQueryResult{
+			NewInlineQueryResultArticle("1", "Arsenal 2:1 Chelsea", "*Arsenal* 2:1 Chelsea", TgParseModMarkdownV2),
+			NewInlineQueryResultCachedPhoto("2", "file-id"),
+		},
+		CacheTime: 30,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := transport.calls("answerInlineQuery")
+	if len(calls) != 1 {
+		t.Fatalf("got %d answerInlineQuery calls, want 1", len(calls))
+	}
+
+	var sent struct {
+		InlineQueryId string                   `json:"inline_query_id"`
+		Results       []map[string]interface{} `json:"results"`
+	}
+	if err := json.Unmarshal(calls[0].Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent.InlineQueryId != "42" || len(sent.Results) != 2 {
+		t.Fatalf("unexpected request: %s", calls[0].Body)
+	}
+
+	if sent.Results[0]["type"] != "article" || sent.Results[1]["type"] != "photo" {
+		t.Errorf("unexpected result types: %v, %v", sent.Results[0]["type"], sent.Results[1]["type"])
+	}
+
+	content, _ := sent.Results[0]["input_message_content"].(map[string]interface{})
+	if content["message_text"] != "*Arsenal* 2:1 Chelsea" || content["parse_mode"] != TgParseModMarkdownV2 {
+		t.Errorf("unexpected input_message_content: %v", content)
+	}
+
+	if sent.Results[1]["photo_file_id"] != "file-id" {
+		t.Errorf("unexpected cached photo: %v", sent.Results[1])
+	}
+}
+
+func TestTelegramClient_AnswerInlineQueryAPIError(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	transport.handler = func(method string, body []byte) (int, string) {
+		return 400, `{"ok":false,"error_code":400,"description":"Bad Request: query is too old"}`
+	}
+
+	err := client.AnswerInlineQuery(AnswerInlineQueryRequest{InlineQueryId: "42"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got %v, want *APIError", err)
+	}
+
+	if apiErr.StatusCode != 400 || apiErr.Description != "Bad Request: query is too old" {
+		t.Errorf("unexpected APIError: %+v", apiErr)
+	}
+}
@@ -0,0 +1,60 @@
+package teledau
+
+import "strconv"
+
+// EditChatInviteLink changes a link created by the bot. Zero fields are removed from the link.
+func (t *TelegramClient) EditChatInviteLink(invite EditChatInviteLinkRequest) (*InviteLink, error) {
+	return t.postInviteLink(TgBotEditChatInviteLinkUrl, invite)
+}
+
+// CreateChatSubscriptionInviteLink creates a paid subscription link for a channel.
+func (t *TelegramClient) CreateChatSubscriptionInviteLink(invite CreateChatSubscriptionInviteLinkRequest) (*InviteLink, error) {
+	return t.postInviteLink(TgBotCreateChatSubscriptionInviteLinkUrl, invite)
+}
+
+// EditChatSubscriptionInviteLink renames a subscription link; its price and period can't be changed.
+func (t *TelegramClient) EditChatSubscriptionInviteLink(chatId, inviteLink, name string) (*InviteLink, error) {
+	return t.postInviteLink(TgBotEditChatSubscriptionInviteLinkUrl, inviteLinkRequest{ChatId: chatId, InviteLink: inviteLink, Name: name})
+}
+
+// RevokeChatInviteLink revokes a link created by the bot. Revoking the primary link generates a new one.
+func (t *TelegramClient) RevokeChatInviteLink(chatId, inviteLink string) (*InviteLink, error) {
+	return t.postInviteLink(TgBotRevokeChatInviteLinkUrl, inviteLinkRequest{ChatId: chatId, InviteLink: inviteLink})
+}
+
+// ExportChatInviteLink generates a new primary invite link, revoking the previous one.
+func (t *TelegramClient) ExportChatInviteLink(chatId string) (string, error) {
+	var response StringResponse
+	if err := t.postJSON(TgBotExportChatInviteLinkUrl, inviteLinkRequest{ChatId: chatId}, &response); err != nil {
+		return "", err
+	}
+
+	return response.Result, nil
+}
+
+func (t *TelegramClient) ApproveChatJoinRequest(chatId string, userId int64) error {
+	return t.postJSON(TgBotApproveChatJoinRequestUrl, chatMemberRequest{ChatId: chatId, UserId: userId}, nil)
+}
+
+func (t *TelegramClient) DeclineChatJoinRequest(chatId string, userId int64) error {
+	return t.postJSON(TgBotDeclineChatJoinRequestUrl, chatMemberRequest{ChatId: chatId, UserId: userId}, nil)
+}
+
+// AnswerChatJoinRequest approves or declines the join request received in Update.ChatJoinRequest.
+func (t *TelegramClient) AnswerChatJoinRequest(request ChatJoinRequest, approve bool) error {
+	chatId := strconv.Itoa(request.Chat.Id)
+	if approve {
+		return t.ApproveChatJoinRequest(chatId, request.From.Id)
+	}
+
+	return t.DeclineChatJoinRequest(chatId, request.From.Id)
+}
+
+func (t *TelegramClient) postInviteLink(method string, payload interface{}) (*InviteLink, error) {
+	var response InviteLinks
+	if err := t.postJSON(method, payload, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
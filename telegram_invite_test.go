@@ -0,0 +1,81 @@
+package teledau
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTelegramClient_InviteLinks(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"createChatInviteLink": `{"ok":true,"result":{"invite_link":"https://t.me/+abc","name":"spring","creates_join_request":true,"expire_date":1700000000}}`,
+		"revokeChatInviteLink": `{"ok":true,"result":{"invite_link":"https://t.me/+abc","is_revoked":true}}`,
+		"exportChatInviteLink": `{"ok":true,"result":"https://t.me/+primary"}`,
+	})
+
+	created, err := client.GenerateInviteLinks(CreateChatInviteLinkRequest{ChatID: "-100", Name: "spring", ExpireDate: 1700000000, CreatesJoinRequest: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Result.InviteLink != "https://t.me/+abc" || created.Result.ExpireDate != 1700000000 {
+		t.Errorf("unexpected link: %+v", created.Result)
+	}
+
+	want := `{"chat_id":"-100","name":"spring","expire_date":1700000000,"creates_join_request":true}`
+	if body := string(transport.calls("createChatInviteLink")[0].Body); body != want {
+		t.Errorf("createChatInviteLink request = %s", body)
+	}
+
+	revoked, err := client.RevokeChatInviteLink("-100", "https://t.me/+abc")
+	if err != nil || !revoked.IsRevoked {
+		t.Errorf("RevokeChatInviteLink() = %+v, %v", revoked, err)
+	}
+
+	primary, err := client.ExportChatInviteLink("-100")
+	if err != nil || primary != "https://t.me/+primary" {
+		t.Errorf("ExportChatInviteLink() = %q, %v", primary, err)
+	}
+}
+
+func TestTelegramClient_AnswerChatJoinRequest(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	var update Update
+	if err := json.Unmarshal([]byte(`{"update_id":1,"chat_join_request":{"chat":{"id":-100,"type":"channel"},"from":{"id":42},"user_chat_id":42,"date":1,"invite_link":{"invite_link":"https://t.me/+abc"}}}`), &update); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AnswerChatJoinRequest(*update.ChatJoinRequest, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AnswerChatJoinRequest(*update.ChatJoinRequest, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := string(transport.calls("approveChatJoinRequest")[0].Body); body != `{"chat_id":"-100","user_id":42}` {
+		t.Errorf("approveChatJoinRequest request = %s", body)
+	}
+
+	if len(transport.calls("declineChatJoinRequest")) != 1 {
+		t.Error("expected a declineChatJoinRequest call")
+	}
+}
+
+func TestChatMember_JoinedViaInviteLink(t *testing.T) {
+	update := ChatMember{
+		OldChatMember: NewChatMember{Status: ChatMemberStatusLeft},
+		NewChatMember: NewChatMember{Status: ChatMemberStatusMember},
+		InviteLink:    InviteLink{InviteLink: "https://t.me/+abc"},
+	}
+
+	if link, ok := update.JoinedViaInviteLink(); !ok || link != "https://t.me/+abc" {
+		t.Errorf("JoinedViaInviteLink() = %q, %v", link, ok)
+	}
+
+	update.OldChatMember.Status = ChatMemberStatusMember
+	update.NewChatMember.Status = ChatMemberStatusAdministrator
+	if _, ok := update.JoinedViaInviteLink(); ok {
+		t.Error("a promotion is not a join")
+	}
+}
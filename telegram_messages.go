@@ -0,0 +1,97 @@
+package teledau
+
+import "sort"
+
+// ForwardMessage forwards a message of any kind.
+func (t *TelegramClient) ForwardMessage(forward ForwardMessageRequest) (SendMessageResponse, error) {
+	var response SendMessageResponse
+	err := t.postJSON(TgBotForwardMsgUrl, forward, &response)
+
+	return response, err
+}
+
+// ForwardMessages forwards messages in ascending order of their ids and returns the ids of the sent
+// messages. Messages that can't be found or forwarded are skipped. More than MaxBulkMessages ids are sent
+// in several requests; on error the ids forwarded so far are returned along with it.
+func (t *TelegramClient) ForwardMessages(forward ForwardMessagesRequest) ([]MessageId, error) {
+	var result []MessageId
+	for _, chunk := range chunkMessageIds(sortedMessageIds(forward.MessageIds)) {
+		forward.MessageIds = chunk
+
+		var response MessageIdsResponse
+		if err := t.postJSON(TgBotForwardMessagesUrl, forward, &response); err != nil {
+			return result, err
+		}
+
+		result = append(result, response.Result...)
+	}
+
+	return result, nil
+}
+
+// CopyMessage copies a message and returns the id of the copy. Service messages, paid media
+// and invoices can't be copied.
+func (t *TelegramClient) CopyMessage(message CopyMessageRequest) (*MessageId, error) {
+	var response MessageIdResponse
+	if err := t.postJSON(TgBotCopyMessageUrl, message, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
+
+// CopyMessages copies messages like ForwardMessages forwards them.
+func (t *TelegramClient) CopyMessages(messages CopyMessagesRequest) ([]MessageId, error) {
+	var result []MessageId
+	for _, chunk := range chunkMessageIds(sortedMessageIds(messages.MessageIds)) {
+		messages.MessageIds = chunk
+
+		var response MessageIdsResponse
+		if err := t.postJSON(TgBotCopyMessagesUrl, messages, &response); err != nil {
+			return result, err
+		}
+
+		result = append(result, response.Result...)
+	}
+
+	return result, nil
+}
+
+// DeleteMessage deletes a message. The Bot API refuses to delete most messages older than 48 hours
+// and messages of others without the can_delete_messages right; such failures are returned as *APIError.
+func (t *TelegramClient) DeleteMessage(chatId string, messageId int) error {
+	return t.postJSON(TgBotDeleteMsgUrl, chatRequest{ChatId: chatId, MessageId: messageId}, nil)
+}
+
+// DeleteMessages deletes messages of a chat, MaxBulkMessages per request. Messages that can't be found are skipped.
+func (t *TelegramClient) DeleteMessages(chatId string, messageIds []int) error {
+	for _, chunk := range chunkMessageIds(messageIds) {
+		if err := t.postJSON(TgBotDeleteMessagesUrl, deleteMessagesRequest{ChatId: chatId, MessageIds: chunk}, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sortedMessageIds returns a sorted copy of ids, as the Bot API expects them in increasing order.
+func sortedMessageIds(ids []int) []int {
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+
+	return sorted
+}
+
+func chunkMessageIds(ids []int) [][]int {
+	var chunks [][]int
+	for len(ids) > MaxBulkMessages {
+		chunks = append(chunks, ids[:MaxBulkMessages])
+		ids = ids[MaxBulkMessages:]
+	}
+
+	if len(ids) > 0 {
+		chunks = append(chunks, ids)
+	}
+
+	return chunks
+}
@@ -0,0 +1,131 @@
+package teledau
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestTelegramClient_ForwardMessage(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"forwardMessage": `{"ok":true,"result":{"message_id":42,"chat":{"id":-100},"forward_origin":{"type":"channel"}}}`,
+	})
+
+	response, err := client.ForwardMessage(ForwardMessageRequest{ChatId: "@archive", FromChatId: "@club", MessageId: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Result.MessageId != 42 {
+		t.Errorf("MessageId = %d, want 42", response.Result.MessageId)
+	}
+
+	var request map[string]interface{}
+	if err := json.Unmarshal(transport.calls("forwardMessage")[0].Body, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if request["from_chat_id"] != "@club" || request["message_id"] != float64(7) {
+		t.Errorf("unexpected request: %v", request)
+	}
+}
+
+func TestTelegramClient_CopyMessage(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"copyMessage": `{"ok":true,"result":{"message_id":43}}`,
+	})
+
+	caption := ""
+	id, err := client.CopyMessage(CopyMessageRequest{
+		ChatId:      "@archive",
+		FromChatId:  "@club",
+		MessageId:   7,
+		Caption:     &caption,
+		ReplyMarkup: InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{{Text: "Open", URL: "https://example.com"}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id.MessageId != 43 {
+		t.Errorf("MessageId = %d, want 43", id.MessageId)
+	}
+
+	var request map[string]interface{}
+	if err := json.Unmarshal(transport.calls("copyMessage")[0].Body, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, ok := request["caption"]; !ok || value != "" {
+		t.Errorf("empty caption override should be sent, got %v", request)
+	}
+
+	if _, ok := request["reply_markup"]; !ok {
+		t.Error("reply_markup should be sent")
+	}
+}
+
+func TestTelegramClient_BulkMessages(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"copyMessages": `{"ok":true,"result":[{"message_id":1},{"message_id":2}]}`,
+	})
+
+	ids := make([]int, 150)
+	for i := range ids {
+		ids[i] = 150 - i
+	}
+
+	copies, err := client.CopyMessages(CopyMessagesRequest{ChatId: "@archive", FromChatId: "@club", MessageIds: ids})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := transport.calls("copyMessages")
+	if len(calls) != 2 || len(copies) != 4 {
+		t.Fatalf("got %d requests and %d copies, want 2 and 4", len(calls), len(copies))
+	}
+
+	var first CopyMessagesRequest
+	if err := json.Unmarshal(calls[0].Body, &first); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(first.MessageIds) != MaxBulkMessages || first.MessageIds[0] != 1 || first.MessageIds[99] != 100 {
+		t.Errorf("first chunk should hold ids 1-100 in order, got %d ids starting at %d", len(first.MessageIds), first.MessageIds[0])
+	}
+
+	if ids[0] != 150 {
+		t.Error("CopyMessages should not reorder the caller's ids")
+	}
+
+	if err := client.DeleteMessages("@club", ids); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls := transport.calls("deleteMessages"); len(calls) != 2 {
+		t.Errorf("got %d deleteMessages requests, want 2", len(calls))
+	}
+}
+
+func TestTelegramClient_DeleteMessage(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+	transport.handler = func(method string, body []byte) (int, string) {
+		return http.StatusBadRequest, `{"ok":false,"error_code":400,"description":"Bad Request: message to delete not found"}`
+	}
+
+	err := client.DeleteMessage("@club", 7)
+
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.ErrorCode != 400 {
+		t.Fatalf("DeleteMessage() = %v, want *APIError", err)
+	}
+
+	var request chatRequest
+	if err := json.Unmarshal(transport.calls("deleteMessage")[0].Body, &request); err != nil {
+		t.Fatal(err)
+	}
+
+	if request.ChatId != "@club" || request.MessageId != 7 {
+		t.Errorf("unexpected request: %+v", request)
+	}
+}
@@ -0,0 +1,132 @@
+package teledau
+
+// SendLongMessage sends message like SendMessage, splitting text longer than MaxMessageLength
+// into several messages with SplitText. Text with a ParseMode is parsed locally first and the parts
+// are sent with entities, so a part never ends inside a tag. ReplyMarkup is attached to the last part only.
+// On error the responses for the parts already sent are returned along with it.
+func (t *TelegramClient) SendLongMessage(message MessageRequest) ([]SendMessageResponse, error) {
+	if UTF16Len(message.Text) <= MaxMessageLength {
+		response, err := t.SendMessage(message)
+		if err != nil {
+			return nil, err
+		}
+
+		return []SendMessageResponse{response}, nil
+	}
+
+	text, entities, err := parseFormatted(message.Text, message.ParseMode, message.Entities)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.sendTextParts(message, SplitText(text, entities, MaxMessageLength))
+}
+
+// SendPhotoWithLongCaption sends photo like SendPhoto. When the caption is longer than MaxCaptionLength,
+// the photo gets as much of it as fits and the rest follows in text messages.
+// The first response is the photo.
+func (t *TelegramClient) SendPhotoWithLongCaption(photo PhotoRequest) ([]SendMessageResponse, error) {
+	caption, rest, err := splitCaption(photo.Caption, photo.ParseMode, photo.CaptionEntities)
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != nil {
+		photo.Caption, photo.CaptionEntities, photo.ParseMode = caption.Text, caption.Entities, ""
+	}
+
+	response, err := t.SendPhoto(photo)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []SendMessageResponse{*response}
+	if len(rest) == 0 {
+		return responses, nil
+	}
+
+	followUps, err := t.sendTextParts(MessageRequest{
+		ChatId:              photo.ChatId,
+		MessageThreadId:     photo.MessageThreadId,
+		DisableNotification: photo.DisableNotification,
+		ProtectContent:      photo.ProtectContent,
+	}, rest)
+
+	return append(responses, followUps...), err
+}
+
+// SendPhotoGroupWithLongCaption sends group like SendPhotoGroup. A caption longer than MaxCaptionLength
+// is cut like in SendPhotoWithLongCaption and the rest is returned as the follow-up messages.
+func (t *TelegramClient) SendPhotoGroupWithLongCaption(group MediaGroupRequest) (*MediaPostResponse, []SendMessageResponse, error) {
+	caption, rest, err := splitCaption(group.Caption, group.ParseMode, group.CaptionEntities)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rest != nil {
+		group.Caption, group.CaptionEntities, group.ParseMode = caption.Text, caption.Entities, ""
+	}
+
+	response, err := t.SendPhotoGroup(group)
+	if err != nil || len(rest) == 0 {
+		return response, nil, err
+	}
+
+	followUps, err := t.sendTextParts(MessageRequest{
+		ChatId:              group.ChatId,
+		MessageThreadId:     group.MessageThreadId,
+		DisableNotification: group.DisableNotification,
+		ProtectContent:      group.ProtectContent,
+	}, rest)
+
+	return response, followUps, err
+}
+
+// sendTextParts sends parts as separate messages based on message, keeping its ReplyMarkup for the last one.
+func (t *TelegramClient) sendTextParts(message MessageRequest, parts []TextPart) ([]SendMessageResponse, error) {
+	responses := make([]SendMessageResponse, 0, len(parts))
+	replyMarkup := message.ReplyMarkup
+
+	for i, part := range parts {
+		message.Text, message.Entities, message.ParseMode = part.Text, part.Entities, ""
+		message.ReplyMarkup = nil
+		if i == len(parts)-1 {
+			message.ReplyMarkup = replyMarkup
+		}
+
+		response, err := t.SendMessage(message)
+		if err != nil {
+			return responses, err
+		}
+
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// splitCaption returns the caption part and the text parts that follow it. The rest is nil
+// when the caption fits, in which case it should be sent unchanged.
+func splitCaption(caption, parseMode string, entities []MessageEntity) (TextPart, []TextPart, error) {
+	if UTF16Len(caption) <= MaxCaptionLength {
+		return TextPart{}, nil, nil
+	}
+
+	text, entities, err := parseFormatted(caption, parseMode, entities)
+	if err != nil {
+		return TextPart{}, nil, err
+	}
+
+	s := newTextSplitter(text, entities)
+	first, _ := s.next(MaxCaptionLength)
+
+	rest := []TextPart{}
+	for {
+		part, ok := s.next(MaxMessageLength)
+		if !ok {
+			return first, rest, nil
+		}
+
+		rest = append(rest, part)
+	}
+}
@@ -0,0 +1,164 @@
+package teledau
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// GetStickerSet returns a sticker set by name.
+func (t *TelegramClient) GetStickerSet(name string) (*StickerSet, error) {
+	var response StickerSetResponse
+	if err := t.postJSON(TgBotGetStickerSetUrl, stickerSetRequest{Name: name}, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
+
+// GetCustomEmojiStickers returns the stickers of up to 200 custom emoji, e.g. those of
+// EntityTypeCustomEmoji entities.
+func (t *TelegramClient) GetCustomEmojiStickers(customEmojiIds []string) ([]Sticker, error) {
+	var response StickersResponse
+	if err := t.postJSON(TgBotGetCustomEmojiStickersUrl, customEmojiStickersRequest{CustomEmojiIds: customEmojiIds}, &response); err != nil {
+		return nil, err
+	}
+
+	return response.Result, nil
+}
+
+// UploadStickerFile uploads a sticker file for later use in CreateNewStickerSet, AddStickerToSet
+// and ReplaceStickerInSet; format is one of the StickerFormat* constants.
+func (t *TelegramClient) UploadStickerFile(userId int64, sticker InputFile, format string) (*File, error) {
+	fields := map[string]string{
+		TgFieldUserId: strconv.FormatInt(userId, 10),
+		TgFieldFormat: format,
+	}
+
+	var response UploadedFileResponse
+	if err := t.postMultipart(TgBotUploadStickerFileUrl, fields, sticker.writeField(fields, TgFieldSticker, TgStickerFileName), &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
+
+// CreateNewStickerSet creates a sticker set with 1 to 50 stickers.
+func (t *TelegramClient) CreateNewStickerSet(set CreateNewStickerSetRequest) error {
+	fields := map[string]string{
+		TgFieldUserId: strconv.FormatInt(set.UserId, 10),
+		TgFieldName:   set.Name,
+		TgFieldTitle:  set.Title,
+	}
+	if len(set.StickerType) > 0 {
+		fields[TgFieldStickerType] = set.StickerType
+	}
+	if set.NeedsRepainting {
+		fields[TgFieldNeedsRepainting] = "true"
+	}
+
+	stickers := make([]inputSticker, len(set.Stickers))
+	var files []multipartFile
+	for i, sticker := range set.Stickers {
+		var stickerFiles []multipartFile
+		stickers[i], stickerFiles = sticker.input(fmt.Sprintf("%s%d", TgFieldSticker, i))
+		files = append(files, stickerFiles...)
+	}
+
+	if err := writeJSONField(fields, TgFieldStickers, stickers); err != nil {
+		return err
+	}
+
+	return t.postMultipart(TgBotCreateNewStickerSetUrl, fields, files, nil)
+}
+
+// AddStickerToSet adds a sticker to a set created by the bot.
+func (t *TelegramClient) AddStickerToSet(userId int64, name string, sticker InputSticker) error {
+	fields := map[string]string{
+		TgFieldUserId: strconv.FormatInt(userId, 10),
+		TgFieldName:   name,
+	}
+
+	files, err := writeInputSticker(fields, sticker)
+	if err != nil {
+		return err
+	}
+
+	return t.postMultipart(TgBotAddStickerToSetUrl, fields, files, nil)
+}
+
+// ReplaceStickerInSet replaces oldSticker, a file_id, keeping its position in the set.
+func (t *TelegramClient) ReplaceStickerInSet(userId int64, name, oldSticker string, sticker InputSticker) error {
+	fields := map[string]string{
+		TgFieldUserId:     strconv.FormatInt(userId, 10),
+		TgFieldName:       name,
+		TgFieldOldSticker: oldSticker,
+	}
+
+	files, err := writeInputSticker(fields, sticker)
+	if err != nil {
+		return err
+	}
+
+	return t.postMultipart(TgBotReplaceStickerInSetUrl, fields, files, nil)
+}
+
+// SetStickerPositionInSet moves a sticker, given by file_id, to a zero-based position in its set.
+func (t *TelegramClient) SetStickerPositionInSet(sticker string, position int) error {
+	return t.postJSON(TgBotSetStickerPositionInSetUrl, stickerPositionRequest{Sticker: sticker, Position: position}, nil)
+}
+
+// DeleteStickerFromSet deletes a sticker, given by file_id, from the set created by the bot.
+func (t *TelegramClient) DeleteStickerFromSet(sticker string) error {
+	return t.postJSON(TgBotDeleteStickerFromSetUrl, stickerRequest{Sticker: sticker}, nil)
+}
+
+// SetStickerEmojiList changes the 1 to 20 emoji of a regular or custom emoji sticker.
+func (t *TelegramClient) SetStickerEmojiList(sticker string, emojiList []string) error {
+	return t.postJSON(TgBotSetStickerEmojiListUrl, stickerEmojiListRequest{Sticker: sticker, EmojiList: emojiList}, nil)
+}
+
+// SetStickerSetThumbnail sets the thumbnail of a regular or mask sticker set; format is the
+// StickerFormat* of the thumbnail. An empty thumbnail makes the first sticker the thumbnail.
+func (t *TelegramClient) SetStickerSetThumbnail(name string, userId int64, thumbnail InputFile, format string) error {
+	fields := map[string]string{
+		TgFieldName:   name,
+		TgFieldUserId: strconv.FormatInt(userId, 10),
+		TgFieldFormat: format,
+	}
+
+	var files []multipartFile
+	if len(thumbnail.FileId) > 0 || thumbnail.Data != nil {
+		files = thumbnail.writeField(fields, TgFieldThumbnail, "thumbnail.webp")
+	}
+
+	return t.postMultipart(TgBotSetStickerSetThumbnailUrl, fields, files, nil)
+}
+
+// SetCustomEmojiStickerSetThumbnail sets the thumbnail of a custom emoji set to one of its emoji;
+// an empty customEmojiId makes the first sticker the thumbnail.
+func (t *TelegramClient) SetCustomEmojiStickerSetThumbnail(name, customEmojiId string) error {
+	return t.postJSON(TgBotSetCustomEmojiStickerSetThumbnailUrl, stickerSetRequest{Name: name, CustomEmojiId: customEmojiId}, nil)
+}
+
+// input returns the JSON form of s and the file to upload under field, if any.
+func (s InputSticker) input(field string) (inputSticker, []multipartFile) {
+	sticker, files := s.Sticker.attach(field, TgStickerFileName)
+
+	return inputSticker{
+		Sticker:      sticker,
+		Format:       s.Format,
+		EmojiList:    s.EmojiList,
+		MaskPosition: s.MaskPosition,
+		Keywords:     s.Keywords,
+	}, files
+}
+
+// writeInputSticker writes sticker into the sticker field of an addStickerToSet or replaceStickerInSet form.
+func writeInputSticker(fields map[string]string, sticker InputSticker) ([]multipartFile, error) {
+	input, files := sticker.input("sticker_file")
+	if err := writeJSONField(fields, TgFieldSticker, input); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
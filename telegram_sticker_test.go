@@ -0,0 +1,103 @@
+package teledau
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTelegramClient_GetStickerSet(t *testing.T) {
+	client, _ := newFakeTelegramClient(map[string]string{
+		"getStickerSet": `{"ok":true,"result":{"name":"club_by_bot","title":"Club","sticker_type":"regular","stickers":[{"file_id":"s1","emoji":"⚽","set_name":"club_by_bot"}]}}`,
+	})
+
+	set, err := client.GetStickerSet("club_by_bot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if set.StickerType != StickerTypeRegular || len(set.Stickers) != 1 || set.Stickers[0].Emoji != "⚽" || set.Stickers[0].SetName != "club_by_bot" {
+		t.Errorf("unexpected sticker set: %+v", set)
+	}
+}
+
+func TestTelegramClient_CreateNewStickerSet(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	err := client.CreateNewStickerSet(CreateNewStickerSetRequest{
+		UserId: 42,
+		Name:   "club_by_bot",
+		Title:  "Club",
+		Stickers: []InputSticker{
+			{Sticker: InputFile{Data: []byte("webp")}, Format: StickerFormatStatic, EmojiList: []string{"⚽"}},
+			{Sticker: InputFile{FileId: "uploaded"}, Format: StickerFormatStatic, EmojiList: []string{"🏆"}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := readMultipartForm(t, transport.calls("createNewStickerSet")[0])
+
+	var stickers []inputSticker
+	if err := json.Unmarshal([]byte(form["stickers"]), &stickers); err != nil {
+		t.Fatal(err)
+	}
+
+	if form["user_id"] != "42" || len(stickers) != 2 || stickers[0].Sticker != "attach://sticker0" || stickers[1].Sticker != "uploaded" {
+		t.Errorf("unexpected form: %v", form)
+	}
+
+	if form["sticker0"] != "webp" {
+		t.Error("sticker file should be uploaded under its attach name")
+	}
+
+	if _, ok := form["sticker1"]; ok {
+		t.Error("sticker given by file_id should not be uploaded")
+	}
+}
+
+func TestTelegramClient_AddStickerToSet(t *testing.T) {
+	client, transport := newFakeTelegramClient(nil)
+
+	sticker := InputSticker{Sticker: InputFile{FileId: "uploaded"}, Format: StickerFormatStatic, EmojiList: []string{"⚽"}}
+	if err := client.AddStickerToSet(42, "club_by_bot", sticker); err != nil {
+		t.Fatal(err)
+	}
+
+	form := readMultipartForm(t, transport.calls("addStickerToSet")[0])
+	if form["name"] != "club_by_bot" || !strings.Contains(form["sticker"], `"sticker":"uploaded"`) {
+		t.Errorf("unexpected form: %v", form)
+	}
+
+	if err := client.SetStickerEmojiList("uploaded", []string{"⚽", "🥅"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := string(transport.calls("setStickerEmojiList")[0].Body); body != `{"sticker":"uploaded","emoji_list":["⚽","🥅"]}` {
+		t.Errorf("unexpected request: %s", body)
+	}
+}
+
+func TestTelegramClient_SendStickerFileName(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"sendSticker": `{"ok":true,"result":{"message_id":1}}`,
+	})
+
+	if _, err := client.SendSticker("@club", base64.StdEncoding.EncodeToString([]byte("webp"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := string(transport.calls("sendSticker")[0].Body); !strings.Contains(body, `filename="`+TgStickerFileName+`"`) {
+		t.Errorf("sticker should be uploaded as %s", TgStickerFileName)
+	}
+
+	if _, err := client.SendStickerMessage(StickerRequest{ChatId: "@club", StickerFile: InputFile{FileId: "s1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if form := readMultipartForm(t, transport.calls("sendSticker")[1]); form["sticker"] != "s1" {
+		t.Errorf("sticker file_id should be sent as a field, got %v", form)
+	}
+}
@@ -1,26 +1,30 @@
 package teledau
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"path"
+	"sync"
 	"testing"
 )
 
 var telegramClient *TelegramClient
 
 //	func TestNewTelegramClient(t *testing.T) {
-//		telegramClient = NewTelegramClient("6699186697:-", context.Background())
+//		telegramClient = NewTelegramClient(context.Background(), "6699186697:-")
 //		if telegramClient == nil {
 //			t.Error("Telegram client is nil")
 //		}
 //	}
 //
 //	func TestTelegramClient_SendMessage(t *testing.T) {
-//		telegramClient = NewTelegramClient("6699186697:-l5ZcqEBxAvf40sSiGCzpk", context.Background())
+//		telegramClient = NewTelegramClient(context.Background(), "6699186697:-l5ZcqEBxAvf40sSiGCzpk")
 //		if telegramClient == nil {
 //			t.Error("Telegram client is nil")
 //		}
@@ -76,7 +80,7 @@ var telegramClient *TelegramClient
 // }
 //
 //	func TestTelegramClient_SendSticker(t *testing.T) {
-//		telegramClient = NewTelegramClient(":-l5ZcqEBxAvf40sSiGCzpk", context.Background())
+//		telegramClient = NewTelegramClient(context.Background(), ":-l5ZcqEBxAvf40sSiGCzpk")
 //		if telegramClient == nil {
 //			t.Error("Telegram client is nil")
 //		}
@@ -93,7 +97,7 @@ var telegramClient *TelegramClient
 //		}
 //	}
 func TestTelegramClient_SendMedia(t *testing.T) {
-	telegramClient = NewTelegramClient("", context.Background())
+	telegramClient = NewTelegramClient(context.Background(), "")
 	if telegramClient == nil {
 		t.Error("Telegram client is nil")
 	}
@@ -107,7 +111,7 @@ func TestTelegramClient_SendMedia(t *testing.T) {
 	log.Printf("%v", resp.Result.MessageId)
 }
 func TestTelegramClient_SendMediaGroup(t *testing.T) {
-	telegramClient = NewTelegramClient("7364006607:AAGK1OQCqe-tmwQnJ-DxsLGcY9eshyIYwI8", context.Background())
+	telegramClient = NewTelegramClient(context.Background(), "7364006607:AAGK1OQCqe-tmwQnJ-DxsLGcY9eshyIYwI8")
 	media, err := getImageBase64FromURL("https://pbs.twimg.com/media/GQ0qcymXsAAbLcU?format=jpg&name=small")
 
 	if err != nil {
@@ -120,7 +124,9 @@ func TestTelegramClient_SendMediaGroup(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	log.Printf("%v", resp.Result.MessageId)
+	for _, message := range resp.Result {
+		log.Printf("%v", message.MessageId)
+	}
 }
 func getImageBase64FromURL(url string) (string, error) {
 	resp, err := http.Get(url)
@@ -143,14 +149,150 @@ func getImageBase64FromURL(url string) (string, error) {
 }
 
 func TestTelegramClient_GetChat(t *testing.T) {
-	telegramClient = NewTelegramClient("", context.Background())
-	if telegramClient == nil {
-		t.Error("Telegram client is nil")
+	client, transport := newFakeTelegramClient(map[string]string{
+		"getChat": `{"ok":true,"result":{"id":-100,"title":"Club","type":"channel","username":"club"}}`,
+	})
+
+	chat, err := client.GetChat("@club")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if chat.GetChat.Id != -100 || chat.GetChat.Title != "Club" {
+		t.Errorf("unexpected chat: %+v", chat.GetChat)
+	}
+
+	if calls := transport.calls("getChat"); len(calls) != 1 {
+		t.Errorf("expected one getChat call, got %d", len(calls))
 	}
-	chat, err := telegramClient.GetChat("@")
+}
+
+func TestTelegramClient_SendMessageOptions(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{"sendMessage": `{"ok":true,"result":{"message_id":1}}`})
+
+	_, err := client.SendMessage(MessageRequest{
+		ChatId:                "@channel",
+		MessageThreadId:       7,
+		Text:                  "reply",
+		DisableWebPagePreview: true,
+		ProtectContent:        true,
+		ReplyParameters:       &ReplyParameters{MessageId: 10, ChatId: "@other", Quote: "quoted"},
+	})
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+
+	var sent map[string]interface{}
+	if err := json.Unmarshal(transport.calls("sendMessage")[0].Body, &sent); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent["message_thread_id"] != float64(7) || sent["protect_content"] != true {
+		t.Errorf("unexpected request: %v", sent)
+	}
+
+	if preview, _ := sent["link_preview_options"].(map[string]interface{}); preview["is_disabled"] != true {
+		t.Errorf("link_preview_options = %v", sent["link_preview_options"])
+	}
+
+	if _, ok := sent["disable_web_page_preview"]; ok {
+		t.Error("deprecated disable_web_page_preview should not be sent")
+	}
+
+	reply, _ := sent["reply_parameters"].(map[string]interface{})
+	if reply["message_id"] != float64(10) || reply["chat_id"] != "@other" || reply["quote"] != "quoted" {
+		t.Errorf("reply_parameters = %v", sent["reply_parameters"])
+	}
+}
+
+func TestTelegramClient_SendPhotoOptions(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{"sendPhoto": `{"ok":true,"result":{"message_id":1}}`})
+
+	_, err := client.SendPhoto(PhotoRequest{
+		ChatId:              "@channel",
+		Photo:               base64.StdEncoding.EncodeToString([]byte("jpeg")),
+		DisableNotification: true,
+		MessageEffectId:     "5104841245755180586",
+		ReplyParameters:     &ReplyParameters{MessageId: 3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := readMultipartForm(t, transport.calls("sendPhoto")[0])
+	if form[TgFieldDisableNotification] != "true" || form[TgFieldMessageEffectId] != "5104841245755180586" {
+		t.Errorf("unexpected form: %v", form)
+	}
+
+	if form[TgFieldReplyParameters] != `{"message_id":3}` {
+		t.Errorf("reply_parameters = %s", form[TgFieldReplyParameters])
+	}
+
+	if _, ok := form[TgFieldMessageThreadId]; ok {
+		t.Error("message_thread_id should be omitted when not set")
+	}
+}
+
+type fakeRequest struct {
+	Method      string
+	ContentType string
+	Body        []byte
+}
+
+// fakeTransport answers Bot API calls locally. Responses are keyed by method name
+// (e.g. "sendMessage"); unknown methods get {"ok":true,"result":true}.
+type fakeTransport struct {
+	mu        sync.Mutex
+	responses map[string]string
+	handler   func(method string, body []byte) (int, string)
+	requests  []fakeRequest
+}
+
+func newFakeTelegramClient(responses map[string]string) (*TelegramClient, *fakeTransport) {
+	transport := &fakeTransport{responses: responses}
+
+	return NewTelegramClientWithClient(context.Background(), "token", http.Client{Transport: transport}), transport
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = io.ReadAll(req.Body)
+	}
+
+	method := path.Base(req.URL.Path)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, fakeRequest{Method: method, ContentType: req.Header.Get(HeaderContentType), Body: body})
+	handler := f.handler
+	response, ok := f.responses[method]
+	f.mu.Unlock()
+
+	status := http.StatusOK
+	if handler != nil {
+		status, response = handler(method, body)
+	} else if !ok {
+		response = `{"ok":true,"result":true}`
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(bytes.NewBufferString(response)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func (f *fakeTransport) calls(method string) []fakeRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var requests []fakeRequest
+	for _, r := range f.requests {
+		if r.Method == method {
+			requests = append(requests, r)
+		}
 	}
-	log.Printf("%v", chat)
 
+	return requests
 }
@@ -0,0 +1,146 @@
+package teledau
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// TextBuilder builds a plain text together with the MessageEntity list describing its formatting,
+// so the result can be sent without a parse mode and without escaping.
+//
+//	b := NewTextBuilder()
+//	b.Bold("Arsenal").Text(" 2:1 ").Link("Chelsea", "https://example.com")
+//	text, entities := b.Build()
+type TextBuilder struct {
+	text     strings.Builder
+	length   int
+	entities []MessageEntity
+}
+
+func NewTextBuilder() *TextBuilder {
+	return &TextBuilder{}
+}
+
+// Text appends s without formatting.
+func (b *TextBuilder) Text(s string) *TextBuilder {
+	b.text.WriteString(s)
+	b.length += UTF16Len(s)
+
+	return b
+}
+
+func (b *TextBuilder) Bold(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeBold}, s)
+}
+
+func (b *TextBuilder) Italic(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeItalic}, s)
+}
+
+func (b *TextBuilder) Underline(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeUnderline}, s)
+}
+
+func (b *TextBuilder) Strikethrough(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeStrikethrough}, s)
+}
+
+func (b *TextBuilder) Spoiler(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeSpoiler}, s)
+}
+
+func (b *TextBuilder) Code(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeCode}, s)
+}
+
+// Pre appends a preformatted block; language may be empty.
+func (b *TextBuilder) Pre(s, language string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypePre, Language: language}, s)
+}
+
+func (b *TextBuilder) Link(s, url string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeTextLink, URL: url}, s)
+}
+
+// Mention appends s as a link to the user's profile; it works for users without a username.
+func (b *TextBuilder) Mention(s string, user From) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeTextMention, User: &user}, s)
+}
+
+// CustomEmoji appends a custom emoji; emoji is the regular emoji shown where custom emoji are unavailable.
+func (b *TextBuilder) CustomEmoji(emoji, customEmojiId string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeCustomEmoji, CustomEmojiId: customEmojiId}, emoji)
+}
+
+func (b *TextBuilder) Blockquote(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeBlockquote}, s)
+}
+
+func (b *TextBuilder) ExpandableBlockquote(s string) *TextBuilder {
+	return b.Entity(MessageEntity{Type: EntityTypeExpandableBlockquote}, s)
+}
+
+// Entity appends s formatted with entity. Offset and Length of entity are ignored and computed from s.
+func (b *TextBuilder) Entity(entity MessageEntity, s string) *TextBuilder {
+	return b.Nested(entity, func(inner *TextBuilder) {
+		inner.Text(s)
+	})
+}
+
+// Nested appends whatever build writes and formats all of it with entity,
+// which allows combining styles, e.g. a bold word inside a link.
+func (b *TextBuilder) Nested(entity MessageEntity, build func(b *TextBuilder)) *TextBuilder {
+	start := b.length
+	index := len(b.entities)
+
+	b.entities = append(b.entities, entity)
+	build(b)
+
+	length := b.length - start
+	if length == 0 {
+		b.entities = append(b.entities[:index], b.entities[index+1:]...)
+
+		return b
+	}
+
+	b.entities[index].Offset = start
+	b.entities[index].Length = length
+
+	return b
+}
+
+// Len returns the length of the text built so far in UTF-16 code units, as Telegram counts it.
+func (b *TextBuilder) Len() int {
+	return b.length
+}
+
+func (b *TextBuilder) String() string {
+	return b.text.String()
+}
+
+func (b *TextBuilder) Entities() []MessageEntity {
+	return append([]MessageEntity(nil), b.entities...)
+}
+
+// Build returns the text and its entities.
+func (b *TextBuilder) Build() (string, []MessageEntity) {
+	return b.String(), b.Entities()
+}
+
+// UTF16Len returns the length of s in UTF-16 code units, the unit of MessageEntity offsets.
+func UTF16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16RuneLen(r)
+	}
+
+	return n
+}
+
+func utf16RuneLen(r rune) int {
+	if r >= 0x10000 && r <= utf8.MaxRune {
+		return 2
+	}
+
+	return 1
+}
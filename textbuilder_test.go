@@ -0,0 +1,121 @@
+package teledau
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"reflect"
+	"testing"
+)
+
+func TestTextBuilder(t *testing.T) {
+	b := NewTextBuilder()
+	b.Text("⚽ ").
+		Bold("Arsenal").
+		Text(" 2:1 ").
+		Nested(MessageEntity{Type: EntityTypeTextLink, URL: "https://example.com"}, func(b *TextBuilder) {
+			b.Text("match ").Italic("report")
+		}).
+		Text("\n").
+		Pre("fmt.Println(\"😀\")", "go").
+		Bold("")
+
+	text, entities := b.Build()
+
+	if text != "⚽ Arsenal 2:1 match report\nfmt.Println(\"😀\")" {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	want := []MessageEntity{
+		{Type: EntityTypeBold, Offset: 2, Length: 7},
+		{Type: EntityTypeTextLink, Offset: 14, Length: 12, URL: "https://example.com"},
+		{Type: EntityTypeItalic, Offset: 20, Length: 6},
+		{Type: EntityTypePre, Offset: 27, Length: 17, Language: "go"},
+	}
+
+	if !reflect.DeepEqual(entities, want) {
+		t.Errorf("entities = %+v, want %+v", entities, want)
+	}
+
+	if b.Len() != 44 {
+		t.Errorf("Len() = %d, want 44", b.Len())
+	}
+}
+
+func TestTelegramClient_SendPhotoWithEntities(t *testing.T) {
+	client, transport := newFakeTelegramClient(map[string]string{
+		"sendPhoto": `{"ok":true,"result":{"message_id":5,"chat":{"id":1,"type":"channel"},"date":1}}`,
+	})
+
+	text, entities := NewTextBuilder().Bold("Goal.").Text(" (1-0)").Build()
+
+	resp, err := client.SendPhoto(PhotoRequest{
+		ChatId:          "@kaz_goal",
+		Photo:           "aGVsbG8=",
+		Caption:         text,
+		CaptionEntities: entities,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Result.MessageId != 5 {
+		t.Errorf("MessageId = %d, want 5", resp.Result.MessageId)
+	}
+
+	calls := transport.calls("sendPhoto")
+	if len(calls) != 1 {
+		t.Fatalf("got %d sendPhoto calls, want 1", len(calls))
+	}
+
+	form := readMultipartForm(t, calls[0])
+
+	if form[TgFieldCaption] != "Goal. (1-0)" || form[TgFieldChatId] != "@kaz_goal" || form[TgFieldMediaType] != "hello" {
+		t.Errorf("unexpected form: %v", form)
+	}
+
+	if _, ok := form[TgFieldNameParseMod]; ok {
+		t.Errorf("parse_mode must not be sent with caption_entities")
+	}
+
+	var sentEntities []MessageEntity
+	if err := json.Unmarshal([]byte(form[TgFieldCaptionEntities]), &sentEntities); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(sentEntities, entities) {
+		t.Errorf("caption_entities = %+v, want %+v", sentEntities, entities)
+	}
+}
+
+func readMultipartForm(t *testing.T, request fakeRequest) map[string]string {
+	t.Helper()
+
+	_, params, err := mime.ParseMediaType(request.ContentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := make(map[string]string)
+	reader := multipart.NewReader(bytes.NewReader(request.Body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		form[part.FormName()] = string(data)
+	}
+
+	return form
+}
@@ -0,0 +1,121 @@
+package teledau
+
+// Type returns the kind of the update, one of the UpdateType* constants,
+// or an empty string if the update carries nothing teledau knows about.
+func (u Update) Type() string {
+	switch {
+	case u.Message != nil:
+		return UpdateTypeMessage
+	case u.EditedMessage != nil:
+		return UpdateTypeEditedMessage
+	case u.ChannelPost != nil:
+		return UpdateTypeChannelPost
+	case u.EditedChannelPost != nil:
+		return UpdateTypeEditedChannelPost
+	case u.BusinessMessage != nil:
+		return UpdateTypeBusinessMessage
+	case u.ChatMember != nil:
+		return UpdateTypeChatMember
+	case u.MyChatMember != nil:
+		return UpdateTypeMyChatMember
+	case u.ChatJoinRequest != nil:
+		return UpdateTypeChatJoinRequest
+	case u.InlineQuery != nil:
+		return UpdateTypeInlineQuery
+	case u.ChosenInlineResult != nil:
+		return UpdateTypeChosenInlineResult
+	case u.Poll != nil:
+		return UpdateTypePoll
+	case u.PollAnswer != nil:
+		return UpdateTypePollAnswer
+	case u.MessageReaction != nil:
+		return UpdateTypeMessageReaction
+	case u.MessageReactionCount != nil:
+		return UpdateTypeMessageReactionCount
+	case u.ShippingQuery != nil:
+		return UpdateTypeShippingQuery
+	case u.PreCheckoutQuery != nil:
+		return UpdateTypePreCheckoutQuery
+	}
+
+	return ""
+}
+
+// EffectiveMessage returns the message carried by the update, whether it is new or edited,
+// sent to a chat, a channel or a business account. It returns nil for other update types.
+func (u Update) EffectiveMessage() *Message {
+	switch {
+	case u.Message != nil:
+		return u.Message
+	case u.EditedMessage != nil:
+		return u.EditedMessage
+	case u.ChannelPost != nil:
+		return u.ChannelPost
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost
+	case u.BusinessMessage != nil:
+		return u.BusinessMessage
+	}
+
+	return nil
+}
+
+// EffectiveChat returns the chat the update happened in, or nil if the update is not bound to a chat
+// (inline queries, payments and anonymous poll state).
+func (u Update) EffectiveChat() *Chat {
+	if message := u.EffectiveMessage(); message != nil {
+		return &message.Chat
+	}
+
+	switch {
+	case u.ChatMember != nil:
+		return &u.ChatMember.Chat
+	case u.MyChatMember != nil:
+		return &u.MyChatMember.Chat
+	case u.ChatJoinRequest != nil:
+		return &u.ChatJoinRequest.Chat
+	case u.MessageReaction != nil:
+		return &u.MessageReaction.Chat
+	case u.MessageReactionCount != nil:
+		return &u.MessageReactionCount.Chat
+	case u.PollAnswer != nil:
+		return u.PollAnswer.VoterChat
+	}
+
+	return nil
+}
+
+// EffectiveUser returns the user who caused the update, or nil if there is none,
+// e.g. for channel posts and anonymous reactions.
+func (u Update) EffectiveUser() *From {
+	if message := u.EffectiveMessage(); message != nil {
+		if message.From.Id == 0 {
+			return nil
+		}
+
+		return &message.From
+	}
+
+	switch {
+	case u.ChatMember != nil:
+		return &u.ChatMember.From
+	case u.MyChatMember != nil:
+		return &u.MyChatMember.From
+	case u.ChatJoinRequest != nil:
+		return &u.ChatJoinRequest.From
+	case u.InlineQuery != nil:
+		return &u.InlineQuery.From
+	case u.ChosenInlineResult != nil:
+		return &u.ChosenInlineResult.From
+	case u.PollAnswer != nil:
+		return u.PollAnswer.User
+	case u.MessageReaction != nil:
+		return u.MessageReaction.User
+	case u.ShippingQuery != nil:
+		return &u.ShippingQuery.From
+	case u.PreCheckoutQuery != nil:
+		return &u.PreCheckoutQuery.From
+	}
+
+	return nil
+}
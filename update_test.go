@@ -0,0 +1,81 @@
+package teledau
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestUpdate_Effective(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantType string
+		wantChat int
+		wantUser int64
+		wantText string
+	}{
+		{
+			name:     "channel post",
+			data:     `{"update_id": 1, "channel_post": {"message_id": 5, "chat": {"id": -100, "type": "channel"}, "date": 1, "text": "news"}}`,
+			wantType: UpdateTypeChannelPost,
+			wantChat: -100,
+			wantText: "news",
+		},
+		{
+			name:     "edited message",
+			data:     `{"update_id": 2, "edited_message": {"message_id": 5, "from": {"id": 7, "first_name": "A"}, "chat": {"id": 7, "type": "private"}, "date": 1, "text": "fixed"}}`,
+			wantType: UpdateTypeEditedMessage,
+			wantChat: 7,
+			wantUser: 7,
+			wantText: "fixed",
+		},
+		{
+			name:     "my chat member",
+			data:     `{"update_id": 3, "my_chat_member": {"chat": {"id": -200, "type": "supergroup"}, "from": {"id": 9, "first_name": "Admin"}, "date": 1, "old_chat_member": {"status": "left"}, "new_chat_member": {"status": "administrator"}}}`,
+			wantType: UpdateTypeMyChatMember,
+			wantChat: -200,
+			wantUser: 9,
+		},
+		{
+			name:     "poll answer",
+			data:     `{"update_id": 4, "poll_answer": {"poll_id": "p", "user": {"id": 11, "first_name": "B"}, "option_ids": [1]}}`,
+			wantType: UpdateTypePollAnswer,
+			wantUser: 11,
+		},
+		{
+			name:     "chat join request",
+			data:     `{"update_id": 5, "chat_join_request": {"chat": {"id": -300, "type": "channel"}, "from": {"id": 12, "first_name": "C"}, "user_chat_id": 12, "date": 1}}`,
+			wantType: UpdateTypeChatJoinRequest,
+			wantChat: -300,
+			wantUser: 12,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var update Update
+			if err := json.Unmarshal([]byte(tt.data), &update); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := update.Type(); got != tt.wantType {
+				t.Errorf("Type() = %q, want %q", got, tt.wantType)
+			}
+
+			chat := update.EffectiveChat()
+			if (chat == nil && tt.wantChat != 0) || (chat != nil && chat.Id != tt.wantChat) {
+				t.Errorf("EffectiveChat() = %+v, want id %d", chat, tt.wantChat)
+			}
+
+			user := update.EffectiveUser()
+			if (user == nil && tt.wantUser != 0) || (user != nil && user.Id != tt.wantUser) {
+				t.Errorf("EffectiveUser() = %+v, want id %d", user, tt.wantUser)
+			}
+
+			message := update.EffectiveMessage()
+			if (message == nil && tt.wantText != "") || (message != nil && message.Text != tt.wantText) {
+				t.Errorf("EffectiveMessage() = %+v, want text %q", message, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,685 @@
+package teledau
+
+import (
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// VP8L limits and parameters of the lossless WebP bitstream.
+const (
+	vp8lSignature      = 0x2f
+	vp8lMaxDimension   = 1 << 14
+	vp8lPredictorBits  = 4
+	vp8lMaxCodeLength  = 15
+	vp8lMaxCopyLength  = 4096
+	vp8lMinCopyLength  = 3
+	vp8lDistanceOffset = 120
+
+	vp8lTransformPredictor     = 0
+	vp8lTransformSubtractGreen = 2
+
+	vp8lNumLiterals    = 256
+	vp8lNumLengthCodes = 24
+	vp8lNumDistCodes   = 40
+)
+
+// vp8lCodeLengthOrder is the order the code lengths of the code length code are written in.
+var vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// vp8lPredictorModes are the predictors EncodeWebP chooses from for each tile.
+var vp8lPredictorModes = []int{1, 2, 4, 7, 12}
+
+// EncodeWebP writes img to w as a lossless WebP image. The colors of fully transparent
+// pixels are not kept.
+func EncodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > vp8lMaxDimension || height > vp8lMaxDimension {
+		return fmt.Errorf("webp: invalid image size %dx%d", width, height)
+	}
+
+	argb := make([]uint32, 0, width*height)
+	hasAlpha := false
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A != 0xff {
+				hasAlpha = true
+			}
+
+			if c.A == 0 {
+				argb = append(argb, 0)
+
+				continue
+			}
+
+			argb = append(argb, uint32(c.A)<<24|uint32(c.R)<<16|uint32(c.G)<<8|uint32(c.B))
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.write(vp8lSignature, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3)
+
+	// Transforms are written in the order they are applied; the decoder undoes them in reverse.
+	bw.write(1, 1)
+	bw.write(vp8lTransformSubtractGreen, 2)
+	subtractGreen(argb)
+
+	bw.write(1, 1)
+	bw.write(vp8lTransformPredictor, 2)
+	bw.write(vp8lPredictorBits-2, 3)
+	modes, tilesX := predictorModes(argb, width, height, vp8lPredictorBits)
+	writeImageData(bw, modes, tilesX, false)
+	residuals := predictorResiduals(argb, modes, width, height, tilesX, vp8lPredictorBits)
+
+	bw.write(0, 1)
+	writeImageData(bw, residuals, width, true)
+
+	data := bw.bytes()
+	padding := len(data) & 1
+
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(12+len(data)+padding))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(len(data)))
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if padding > 0 {
+		_, err := w.Write([]byte{0})
+
+		return err
+	}
+
+	return nil
+}
+
+// webpSize returns the canvas size of a WebP file from its first chunk.
+func webpSize(data []byte) (int, int, error) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, fmt.Errorf("webp: invalid header")
+	}
+
+	switch string(data[12:16]) {
+	case "VP8L":
+		if data[20] != vp8lSignature {
+			return 0, 0, fmt.Errorf("webp: invalid VP8L signature")
+		}
+
+		v := binary.LittleEndian.Uint32(data[21:25])
+
+		return int(v&0x3fff) + 1, int(v>>14&0x3fff) + 1, nil
+	case "VP8X":
+		width := int(data[24]) | int(data[25])<<8 | int(data[26])<<16
+		height := int(data[27]) | int(data[28])<<8 | int(data[29])<<16
+
+		return width + 1, height + 1, nil
+	case "VP8 ":
+		if data[23] != 0x9d || data[24] != 0x01 || data[25] != 0x2a {
+			return 0, 0, fmt.Errorf("webp: invalid VP8 start code")
+		}
+
+		width := binary.LittleEndian.Uint16(data[26:28]) & 0x3fff
+		height := binary.LittleEndian.Uint16(data[28:30]) & 0x3fff
+
+		return int(width), int(height), nil
+	}
+
+	return 0, 0, fmt.Errorf("webp: unknown chunk %q", data[12:16])
+}
+
+// bitWriter packs values least significant bit first, as VP8L reads them.
+type bitWriter struct {
+	buf []byte
+	acc uint64
+	n   uint
+}
+
+func (w *bitWriter) write(value uint32, n uint) {
+	w.acc |= uint64(value) << w.n
+	w.n += n
+	for w.n >= 8 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc >>= 8
+		w.n -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.n > 0 {
+		w.buf = append(w.buf, byte(w.acc))
+		w.acc, w.n = 0, 0
+	}
+
+	return w.buf
+}
+
+// subtractGreen applies the subtract green transform in place.
+func subtractGreen(argb []uint32) {
+	for i, p := range argb {
+		green := p >> 8 & 0xff
+		red := (p>>16 - green) & 0xff
+		blue := (p - green) & 0xff
+		argb[i] = p&0xff00ff00 | red<<16 | blue
+	}
+}
+
+// predictorModes picks the predictor with the smallest residuals for every tile and returns
+// them as the predictor sub-image, with the mode in the green channel.
+func predictorModes(argb []uint32, width, height int, tileBits uint) ([]uint32, int) {
+	tileSize := 1 << tileBits
+	tilesX := (width + tileSize - 1) >> tileBits
+	tilesY := (height + tileSize - 1) >> tileBits
+
+	modes := make([]uint32, tilesX*tilesY)
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			best, bestCost := vp8lPredictorModes[0], -1
+			for _, mode := range vp8lPredictorModes {
+				cost := 0
+				for y := ty << tileBits; y < height && y < (ty+1)<<tileBits; y++ {
+					for x := tx << tileBits; x < width && x < (tx+1)<<tileBits; x++ {
+						cost += residualCost(subPixels(argb[y*width+x], predict(mode, argb, x, y, width)))
+					}
+				}
+
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+
+			modes[ty*tilesX+tx] = 0xff000000 | uint32(best)<<8
+		}
+	}
+
+	return modes, tilesX
+}
+
+// predictorResiduals returns the difference of every pixel from the prediction of its tile's mode.
+func predictorResiduals(argb, modes []uint32, width, height, tilesX int, tileBits uint) []uint32 {
+	residuals := make([]uint32, len(argb))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			mode := int(modes[(y>>tileBits)*tilesX+x>>tileBits] >> 8 & 0xff)
+			residuals[y*width+x] = subPixels(argb[y*width+x], predict(mode, argb, x, y, width))
+		}
+	}
+
+	return residuals
+}
+
+// predict returns the prediction of the pixel at x, y from its decoded neighbours.
+// The first row and column have fixed predictors whatever the mode.
+func predict(mode int, argb []uint32, x, y, width int) uint32 {
+	i := y*width + x
+	switch {
+	case x == 0 && y == 0:
+		return 0xff000000
+	case y == 0:
+		return argb[i-1]
+	case x == 0:
+		return argb[i-width]
+	}
+
+	left, top, topLeft := argb[i-1], argb[i-width], argb[i-width-1]
+	switch mode {
+	case 1:
+		return left
+	case 2:
+		return top
+	case 4:
+		return topLeft
+	case 7:
+		return average2(left, top)
+	case 12:
+		return clampAddSubtractFull(left, top, topLeft)
+	}
+
+	return 0xff000000
+}
+
+func average2(a, b uint32) uint32 {
+	var p uint32
+	for shift := uint(0); shift < 32; shift += 8 {
+		p |= ((a>>shift&0xff + b>>shift&0xff) / 2) << shift
+	}
+
+	return p
+}
+
+func clampAddSubtractFull(a, b, c uint32) uint32 {
+	var p uint32
+	for shift := uint(0); shift < 32; shift += 8 {
+		v := int(a>>shift&0xff) + int(b>>shift&0xff) - int(c>>shift&0xff)
+		if v < 0 {
+			v = 0
+		} else if v > 0xff {
+			v = 0xff
+		}
+
+		p |= uint32(v) << shift
+	}
+
+	return p
+}
+
+// addPixels and subPixels work on each channel modulo 256.
+func addPixels(a, b uint32) uint32 {
+	ag := (a & 0xff00ff00) + (b & 0xff00ff00)
+	rb := (a & 0x00ff00ff) + (b & 0x00ff00ff)
+
+	return ag&0xff00ff00 | rb&0x00ff00ff
+}
+
+func subPixels(a, b uint32) uint32 {
+	ag := 0x00ff00ff + (a & 0xff00ff00) - (b & 0xff00ff00)
+	rb := 0xff00ff00 + (a & 0x00ff00ff) - (b & 0x00ff00ff)
+
+	return ag&0xff00ff00 | rb&0x00ff00ff
+}
+
+// residualCost estimates how well a residual compresses: small channel values are cheap.
+func residualCost(p uint32) int {
+	cost := 0
+	for shift := uint(0); shift < 32; shift += 8 {
+		v := int(int8(p >> shift))
+		if v < 0 {
+			v = -v
+		}
+
+		cost += v
+	}
+
+	return cost
+}
+
+// vp8lToken is a literal pixel, or a backward reference when length > 0.
+type vp8lToken struct {
+	pixel    uint32
+	length   int
+	distance int
+}
+
+// backwardReferences finds runs that repeat the previous pixel or the pixel above.
+func backwardReferences(argb []uint32, width int) []vp8lToken {
+	tokens := make([]vp8lToken, 0, len(argb))
+	for i := 0; i < len(argb); {
+		best, bestDistance := 0, 0
+		for _, distance := range [2]int{1, width} {
+			if distance > i {
+				continue
+			}
+
+			n := 0
+			for i+n < len(argb) && n < vp8lMaxCopyLength && argb[i+n] == argb[i+n-distance] {
+				n++
+			}
+
+			if n > best {
+				best, bestDistance = n, distance
+			}
+		}
+
+		if best >= vp8lMinCopyLength {
+			tokens = append(tokens, vp8lToken{length: best, distance: bestDistance})
+			i += best
+
+			continue
+		}
+
+		tokens = append(tokens, vp8lToken{pixel: argb[i]})
+		i++
+	}
+
+	return tokens
+}
+
+// prefixEncode splits a length or distance code into its prefix symbol and extra bits.
+func prefixEncode(value int) (int, uint, uint32) {
+	x := value - 1
+	if x < 4 {
+		return x, 0, 0
+	}
+
+	high := bits.Len(uint(x)) - 1
+	second := (x >> (high - 1)) & 1
+	extraBits := uint(high - 1)
+
+	return 2*high + second, extraBits, uint32(x) & (1<<extraBits - 1)
+}
+
+// writeImageData writes argb as entropy-coded image data without a color cache and with
+// a single group of prefix codes.
+func writeImageData(w *bitWriter, argb []uint32, width int, isMain bool) {
+	w.write(0, 1)
+	if isMain {
+		w.write(0, 1)
+	}
+
+	tokens := backwardReferences(argb, width)
+
+	counts := [5][]int{
+		make([]int, vp8lNumLiterals+vp8lNumLengthCodes),
+		make([]int, vp8lNumLiterals),
+		make([]int, vp8lNumLiterals),
+		make([]int, vp8lNumLiterals),
+		make([]int, vp8lNumDistCodes),
+	}
+	for _, t := range tokens {
+		if t.length > 0 {
+			lengthCode, _, _ := prefixEncode(t.length)
+			distanceCode, _, _ := prefixEncode(t.distance + vp8lDistanceOffset)
+			counts[0][vp8lNumLiterals+lengthCode]++
+			counts[4][distanceCode]++
+
+			continue
+		}
+
+		counts[0][t.pixel>>8&0xff]++
+		counts[1][t.pixel>>16&0xff]++
+		counts[2][t.pixel&0xff]++
+		counts[3][t.pixel>>24]++
+	}
+
+	var codes [5]prefixCode
+	for i := range codes {
+		codes[i] = writePrefixCode(w, counts[i])
+	}
+
+	for _, t := range tokens {
+		if t.length > 0 {
+			lengthCode, extraBits, extra := prefixEncode(t.length)
+			codes[0].writeSymbol(w, vp8lNumLiterals+lengthCode)
+			w.write(extra, extraBits)
+
+			distanceCode, extraBits, extra := prefixEncode(t.distance + vp8lDistanceOffset)
+			codes[4].writeSymbol(w, distanceCode)
+			w.write(extra, extraBits)
+
+			continue
+		}
+
+		codes[0].writeSymbol(w, int(t.pixel>>8&0xff))
+		codes[1].writeSymbol(w, int(t.pixel>>16&0xff))
+		codes[2].writeSymbol(w, int(t.pixel&0xff))
+		codes[3].writeSymbol(w, int(t.pixel>>24))
+	}
+}
+
+// prefixCode is a canonical Huffman code. A code with a single symbol takes no bits.
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint32
+}
+
+func newPrefixCode(lengths []uint8) prefixCode {
+	var count [vp8lMaxCodeLength + 1]int
+	used := 0
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+			used++
+		}
+	}
+
+	var next [vp8lMaxCodeLength + 1]int
+	code := 0
+	for l := 1; l <= vp8lMaxCodeLength; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	c := prefixCode{lengths: make([]uint8, len(lengths)), codes: make([]uint32, len(lengths))}
+	for symbol, l := range lengths {
+		if l == 0 || used == 1 {
+			continue
+		}
+
+		// Codes are written from their most significant bit.
+		c.lengths[symbol] = l
+		c.codes[symbol] = uint32(bits.Reverse16(uint16(next[l])) >> (16 - l))
+		next[l]++
+	}
+
+	return c
+}
+
+func (c prefixCode) writeSymbol(w *bitWriter, symbol int) {
+	w.write(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+// writePrefixCode writes the prefix code for the symbol counts and returns it.
+func writePrefixCode(w *bitWriter, counts []int) prefixCode {
+	var used []int
+	for symbol, n := range counts {
+		if n > 0 {
+			used = append(used, symbol)
+		}
+	}
+
+	if len(used) == 0 {
+		used = append(used, 0)
+	}
+
+	// Simple code: one or two symbols below 256.
+	if len(used) <= 2 && used[len(used)-1] < 256 {
+		w.write(1, 1)
+		w.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			w.write(0, 1)
+			w.write(uint32(used[0]), 1)
+		} else {
+			w.write(1, 1)
+			w.write(uint32(used[0]), 8)
+		}
+
+		lengths := make([]uint8, len(counts))
+		lengths[used[0]] = 1
+		if len(used) == 2 {
+			w.write(uint32(used[1]), 8)
+			lengths[used[1]] = 1
+		}
+
+		return newPrefixCode(lengths)
+	}
+
+	w.write(0, 1)
+
+	lengths := huffmanLengths(counts, vp8lMaxCodeLength)
+
+	// Runs of zero lengths are written with the repeat codes 17 and 18.
+	type lengthToken struct {
+		symbol int
+		extra  uint32
+	}
+
+	var tokens []lengthToken
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, lengthToken{symbol: int(lengths[i])})
+			i++
+
+			continue
+		}
+
+		run := 0
+		for i+run < len(lengths) && lengths[i+run] == 0 {
+			run++
+		}
+		i += run
+
+		for run > 0 {
+			switch {
+			case run < 3:
+				tokens = append(tokens, lengthToken{symbol: 0})
+				run--
+			case run <= 10:
+				tokens = append(tokens, lengthToken{symbol: 17, extra: uint32(run - 3)})
+				run = 0
+			default:
+				n := run
+				if n > 138 {
+					n = 138
+				}
+
+				tokens = append(tokens, lengthToken{symbol: 18, extra: uint32(n - 11)})
+				run -= n
+			}
+		}
+	}
+
+	lengthCounts := make([]int, len(vp8lCodeLengthOrder))
+	for _, t := range tokens {
+		lengthCounts[t.symbol]++
+	}
+
+	lengthLengths := huffmanLengths(lengthCounts, 7)
+	lengthCode := newPrefixCode(lengthLengths)
+
+	n := 4
+	for i, symbol := range vp8lCodeLengthOrder {
+		if lengthLengths[symbol] > 0 && i+1 > n {
+			n = i + 1
+		}
+	}
+
+	w.write(uint32(n-4), 4)
+	for _, symbol := range vp8lCodeLengthOrder[:n] {
+		w.write(uint32(lengthLengths[symbol]), 3)
+	}
+
+	// All code lengths of the alphabet follow.
+	w.write(0, 1)
+
+	for _, t := range tokens {
+		lengthCode.writeSymbol(w, t.symbol)
+		switch t.symbol {
+		case 17:
+			w.write(t.extra, 3)
+		case 18:
+			w.write(t.extra, 7)
+		}
+	}
+
+	return newPrefixCode(lengths)
+}
+
+// huffmanLengths returns Huffman code lengths of at most maxLength for the symbol counts.
+// Rare symbols are made more frequent until the tree is shallow enough.
+func huffmanLengths(counts []int, maxLength int) []uint8 {
+	lengths := make([]uint8, len(counts))
+
+	var symbols []int
+	for symbol, n := range counts {
+		if n > 0 {
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	switch len(symbols) {
+	case 0:
+		return lengths
+	case 1:
+		lengths[symbols[0]] = 1
+
+		return lengths
+	}
+
+	for minCount := 1; ; minCount *= 2 {
+		weights := make([]int, len(symbols))
+		for i, symbol := range symbols {
+			weights[i] = counts[symbol]
+			if weights[i] < minCount {
+				weights[i] = minCount
+			}
+		}
+
+		depths := huffmanDepths(weights)
+
+		deepest := 0
+		for _, d := range depths {
+			if d > deepest {
+				deepest = d
+			}
+		}
+
+		if deepest <= maxLength {
+			for i, symbol := range symbols {
+				lengths[symbol] = uint8(depths[i])
+			}
+
+			return lengths
+		}
+	}
+}
+
+// huffmanDepths returns the depth of each leaf of a Huffman tree built from weights.
+func huffmanDepths(weights []int) []int {
+	leaves := make([]int, len(weights))
+	for i := range leaves {
+		leaves[i] = i
+	}
+
+	sort.SliceStable(leaves, func(i, j int) bool { return weights[leaves[i]] < weights[leaves[j]] })
+
+	// Nodes 0..len(weights)-1 are the leaves; merged nodes are appended in order of weight,
+	// so the two smallest nodes are always at the front of one of the two queues.
+	nodeWeights := append([]int(nil), weights...)
+	parents := make([]int, len(weights), 2*len(weights)-1)
+
+	var merged []int
+	pop := func() int {
+		if len(merged) == 0 || (len(leaves) > 0 && nodeWeights[leaves[0]] <= nodeWeights[merged[0]]) {
+			node := leaves[0]
+			leaves = leaves[1:]
+
+			return node
+		}
+
+		node := merged[0]
+		merged = merged[1:]
+
+		return node
+	}
+
+	for len(leaves)+len(merged) > 1 {
+		a, b := pop(), pop()
+		node := len(nodeWeights)
+		nodeWeights = append(nodeWeights, nodeWeights[a]+nodeWeights[b])
+		parents = append(parents, -1)
+		parents[a], parents[b] = node, node
+		merged = append(merged, node)
+	}
+
+	root := len(nodeWeights) - 1
+	parents[root] = -1
+
+	depths := make([]int, len(weights))
+	for i := range depths {
+		for node := i; parents[node] >= 0; node = parents[node] {
+			depths[i]++
+		}
+	}
+
+	return depths
+}
@@ -0,0 +1,474 @@
+package teledau
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestEncodeWebP_RoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	noise := image.NewNRGBA(image.Rect(0, 0, 37, 19))
+	rng.Read(noise.Pix)
+
+	gradient := image.NewNRGBA(image.Rect(0, 0, 300, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 300; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: uint8(255 - y/2)})
+		}
+	}
+
+	flat := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for i := 0; i < len(flat.Pix); i += 4 {
+		copy(flat.Pix[i:], []byte{0x20, 0x80, 0xc0, 0xff})
+	}
+
+	// Few colors in large areas, like a sticker drawing, with a transparent background.
+	drawing := image.NewNRGBA(image.Rect(0, 0, 512, 300))
+	for y := 0; y < 300; y++ {
+		for x := 0; x < 512; x++ {
+			dx, dy := x-256, y-150
+			switch {
+			case dx*dx+dy*dy < 100*100:
+				drawing.SetNRGBA(x, y, color.NRGBA{R: 0xff, G: 0xcc, A: 0xff})
+			case dx*dx+dy*dy < 120*120:
+				drawing.SetNRGBA(x, y, color.NRGBA{A: 0xff})
+			}
+		}
+	}
+
+	offset := image.NewRGBA(image.Rect(5, 7, 25, 18))
+	rng.Read(offset.Pix)
+	for i := 3; i < len(offset.Pix); i += 4 {
+		offset.Pix[i] = 0xff
+	}
+
+	for name, img := range map[string]image.Image{
+		"noise":    noise,
+		"gradient": gradient,
+		"flat":     flat,
+		"drawing":  drawing,
+		"pixel":    image.NewNRGBA(image.Rect(0, 0, 1, 1)),
+		"column":   image.NewGray(image.Rect(0, 0, 1, 300)),
+		"offset":   offset,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeWebP(&buf, img); err != nil {
+				t.Fatalf("EncodeWebP returned error: %v", err)
+			}
+
+			decoded, err := decodeTestVP8L(buf.Bytes())
+			if err != nil {
+				t.Fatalf("decoding failed: %v", err)
+			}
+
+			b := img.Bounds()
+			if decoded.Bounds().Dx() != b.Dx() || decoded.Bounds().Dy() != b.Dy() {
+				t.Fatalf("decoded size %v, want %v", decoded.Bounds().Size(), b.Size())
+			}
+
+			for y := 0; y < b.Dy(); y++ {
+				for x := 0; x < b.Dx(); x++ {
+					want := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+					if want.A == 0 {
+						want = color.NRGBA{}
+					}
+
+					if got := decoded.NRGBAAt(x, y); got != want {
+						t.Fatalf("pixel %d,%d = %v, want %v", x, y, got, want)
+					}
+				}
+			}
+
+			width, height, err := webpSize(buf.Bytes())
+			if err != nil || width != b.Dx() || height != b.Dy() {
+				t.Fatalf("webpSize = %d, %d, %v", width, height, err)
+			}
+		})
+	}
+}
+
+func TestEncodeWebP_Compresses(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 512, 512))
+	for y := 0; y < 512; y++ {
+		for x := 0; x < 512; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x / 2), G: uint8(y / 2), B: 0x80, A: 0xff})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeWebP(&buf, img); err != nil {
+		t.Fatalf("EncodeWebP returned error: %v", err)
+	}
+
+	if buf.Len() > 64*1024 {
+		t.Fatalf("gradient encoded to %d bytes", buf.Len())
+	}
+}
+
+func TestEncodeWebP_InvalidSize(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeWebP(&buf, image.NewNRGBA(image.Rect(0, 0, 0, 10))); err == nil {
+		t.Fatal("expected an error for an empty image")
+	}
+}
+
+func TestHuffmanLengths_Limit(t *testing.T) {
+	// Fibonacci counts make the deepest possible Huffman tree.
+	counts := make([]int, 40)
+	a, b := 1, 1
+	for i := range counts {
+		counts[i] = a
+		a, b = b, a+b
+	}
+
+	lengths := huffmanLengths(counts, 15)
+
+	kraft := 0.0
+	for symbol, l := range lengths {
+		if l == 0 || l > 15 {
+			t.Fatalf("symbol %d has length %d", symbol, l)
+		}
+
+		kraft += 1 / float64(int(1)<<l)
+	}
+
+	if kraft != 1 {
+		t.Fatalf("code is not complete, Kraft sum %v", kraft)
+	}
+}
+
+func TestWebpSize(t *testing.T) {
+	vp8x := make([]byte, 30)
+	copy(vp8x, "RIFF\x00\x00\x00\x00WEBPVP8X")
+	vp8x[24], vp8x[25], vp8x[27] = 0xff, 0x01, 0xff
+
+	vp8 := make([]byte, 30)
+	copy(vp8, "RIFF\x00\x00\x00\x00WEBPVP8 ")
+	copy(vp8[23:], []byte{0x9d, 0x01, 0x2a})
+	binary.LittleEndian.PutUint16(vp8[26:], 512)
+	binary.LittleEndian.PutUint16(vp8[28:], 300)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		width, height int
+	}{
+		{name: "VP8X", data: vp8x, width: 512, height: 256},
+		{name: "VP8", data: vp8, width: 512, height: 300},
+	}
+
+	for _, tt := range tests {
+		width, height, err := webpSize(tt.data)
+		if err != nil || width != tt.width || height != tt.height {
+			t.Fatalf("%s: webpSize = %d, %d, %v", tt.name, width, height, err)
+		}
+	}
+
+	if _, _, err := webpSize([]byte("RIFF")); err == nil {
+		t.Fatal("expected an error for a truncated file")
+	}
+}
+
+// decodeTestVP8L decodes the subset of lossless WebP EncodeWebP produces, following the
+// VP8L specification independently of the encoder's bit writing.
+func decodeTestVP8L(data []byte) (img *image.NRGBA, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	if len(data) < 21 || string(data[0:4]) != "RIFF" || string(data[8:16]) != "WEBPVP8L" {
+		return nil, fmt.Errorf("invalid header %q", data[:16])
+	}
+
+	riffSize := int(binary.LittleEndian.Uint32(data[4:]))
+	chunkSize := int(binary.LittleEndian.Uint32(data[16:]))
+	if riffSize+8 != len(data) || 20+chunkSize+chunkSize&1 != len(data) {
+		return nil, fmt.Errorf("sizes %d and %d don't match file of %d bytes", riffSize, chunkSize, len(data))
+	}
+
+	r := &testBitReader{data: data[20 : 20+chunkSize]}
+	if r.read(8) != vp8lSignature {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	width, height := int(r.read(14))+1, int(r.read(14))+1
+	r.read(1)
+	if r.read(3) != 0 {
+		return nil, fmt.Errorf("invalid version")
+	}
+
+	type transform struct {
+		kind     uint32
+		bits     uint
+		tilesX   int
+		subImage []uint32
+	}
+
+	var transforms []transform
+	for r.read(1) == 1 {
+		tr := transform{kind: r.read(2)}
+		switch tr.kind {
+		case vp8lTransformSubtractGreen:
+		case vp8lTransformPredictor:
+			tr.bits = uint(r.read(3)) + 2
+			tr.tilesX = (width + 1<<tr.bits - 1) >> tr.bits
+			tilesY := (height + 1<<tr.bits - 1) >> tr.bits
+			tr.subImage, err = decodeTestImageData(r, tr.tilesX, tilesY, false)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported transform %d", tr.kind)
+		}
+
+		transforms = append(transforms, tr)
+	}
+
+	argb, err := decodeTestImageData(r, width, height, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(transforms) - 1; i >= 0; i-- {
+		tr := transforms[i]
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				p := &argb[y*width+x]
+				switch tr.kind {
+				case vp8lTransformSubtractGreen:
+					green := *p >> 8 & 0xff
+					*p = *p&0xff00ff00 | (*p>>16+green)&0xff<<16 | (*p+green)&0xff
+				case vp8lTransformPredictor:
+					mode := int(tr.subImage[(y>>tr.bits)*tr.tilesX+x>>tr.bits] >> 8 & 0xff)
+					*p = addPixels(*p, predict(mode, argb, x, y, width))
+				}
+			}
+		}
+	}
+
+	img = image.NewNRGBA(image.Rect(0, 0, width, height))
+	for i, p := range argb {
+		copy(img.Pix[i*4:], []byte{byte(p >> 16), byte(p >> 8), byte(p), byte(p >> 24)})
+	}
+
+	return img, nil
+}
+
+func decodeTestImageData(r *testBitReader, width, height int, isMain bool) ([]uint32, error) {
+	if r.read(1) != 0 {
+		return nil, fmt.Errorf("unexpected color cache")
+	}
+
+	if isMain && r.read(1) != 0 {
+		return nil, fmt.Errorf("unexpected meta prefix codes")
+	}
+
+	var codes [5]*testHuffman
+	for i, size := range []int{vp8lNumLiterals + vp8lNumLengthCodes, 256, 256, 256, vp8lNumDistCodes} {
+		code, err := readTestHuffman(r, size)
+		if err != nil {
+			return nil, err
+		}
+
+		codes[i] = code
+	}
+
+	argb := make([]uint32, width*height)
+	for i := 0; i < len(argb); {
+		green := codes[0].decode(r)
+		if green < 256 {
+			red, blue, alpha := codes[1].decode(r), codes[2].decode(r), codes[3].decode(r)
+			argb[i] = uint32(alpha)<<24 | uint32(red)<<16 | uint32(green)<<8 | uint32(blue)
+			i++
+
+			continue
+		}
+
+		length := testPrefixValue(r, green-256)
+		distanceCode := testPrefixValue(r, codes[4].decode(r))
+		if distanceCode <= vp8lDistanceOffset {
+			return nil, fmt.Errorf("unexpected distance map code %d", distanceCode)
+		}
+
+		distance := distanceCode - vp8lDistanceOffset
+		if distance > i || i+length > len(argb) {
+			return nil, fmt.Errorf("copy of %d at distance %d out of bounds at %d", length, distance, i)
+		}
+
+		for ; length > 0; length-- {
+			argb[i] = argb[i-distance]
+			i++
+		}
+	}
+
+	return argb, nil
+}
+
+func testPrefixValue(r *testBitReader, code int) int {
+	if code < 4 {
+		return code + 1
+	}
+
+	extraBits := uint(code-2) >> 1
+	offset := (2 + code&1) << extraBits
+
+	return offset + int(r.read(extraBits)) + 1
+}
+
+type testBitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *testBitReader) read(n uint) uint32 {
+	var v uint32
+	for i := uint(0); i < n; i++ {
+		if r.pos>>3 >= len(r.data) {
+			panic("read past the end of the data")
+		}
+
+		v |= uint32(r.data[r.pos>>3]>>(r.pos&7)&1) << i
+		r.pos++
+	}
+
+	return v
+}
+
+// testHuffman maps length<<16|code to symbols; a code of one symbol reads no bits.
+type testHuffman struct {
+	symbols map[int]int
+	single  int
+}
+
+func readTestHuffman(r *testBitReader, size int) (*testHuffman, error) {
+	lengths := make([]int, size)
+
+	if r.read(1) == 1 {
+		n := r.read(1) + 1
+		first := r.read(uint(1 + 7*r.read(1)))
+		lengths[first] = 1
+		if n == 2 {
+			lengths[r.read(8)] = 1
+		}
+
+		return newTestHuffman(lengths)
+	}
+
+	lengthLengths := make([]int, len(vp8lCodeLengthOrder))
+	n := int(r.read(4)) + 4
+	for _, symbol := range vp8lCodeLengthOrder[:n] {
+		lengthLengths[symbol] = int(r.read(3))
+	}
+
+	lengthCode, err := newTestHuffman(lengthLengths)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.read(1) != 0 {
+		return nil, fmt.Errorf("unexpected max_symbol")
+	}
+
+	previous := 8
+	for i := 0; i < size; {
+		symbol := lengthCode.decode(r)
+		switch {
+		case symbol < 16:
+			lengths[i] = symbol
+			if symbol != 0 {
+				previous = symbol
+			}
+			i++
+		case symbol == 16:
+			for n := 3 + int(r.read(2)); n > 0; n-- {
+				lengths[i] = previous
+				i++
+			}
+		default:
+			var n int
+			if symbol == 17 {
+				n = 3 + int(r.read(3))
+			} else {
+				n = 11 + int(r.read(7))
+			}
+
+			if i+n > size {
+				return nil, fmt.Errorf("code lengths overflow the alphabet")
+			}
+
+			i += n
+		}
+	}
+
+	return newTestHuffman(lengths)
+}
+
+func newTestHuffman(lengths []int) (*testHuffman, error) {
+	h := &testHuffman{symbols: make(map[int]int), single: -1}
+
+	var count [16]int
+	used := 0
+	for symbol, l := range lengths {
+		if l > 0 {
+			count[l]++
+			used++
+			h.single = symbol
+		}
+	}
+
+	if used == 0 {
+		return nil, fmt.Errorf("empty prefix code")
+	}
+
+	if used == 1 {
+		return h, nil
+	}
+
+	h.single = -1
+	kraft := 0
+	code := 0
+	var next [16]int
+	for l := 1; l < 16; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+		kraft += count[l] << (15 - l)
+	}
+
+	if kraft != 1<<15 {
+		return nil, fmt.Errorf("incomplete prefix code")
+	}
+
+	for symbol, l := range lengths {
+		if l > 0 {
+			h.symbols[l<<16|next[l]] = symbol
+			next[l]++
+		}
+	}
+
+	return h, nil
+}
+
+func (h *testHuffman) decode(r *testBitReader) int {
+	if h.single >= 0 {
+		return h.single
+	}
+
+	code := 0
+	for l := 1; l < 16; l++ {
+		code = code<<1 | int(r.read(1))
+		if symbol, ok := h.symbols[l<<16|code]; ok {
+			return symbol
+		}
+	}
+
+	panic("invalid prefix code")
+}